@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/mpppk/grouping/domain"
+)
+
+// parseAvailability parses availability file. nil is returned if the file is not specified.
+func parseAvailability(filePath string) (*domain.Availability, error) {
+	if filePath == "" {
+		return nil, nil
+	}
+	availability, err := domain.ParseAvailabilityFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse availability file from %s: %w", filePath, err)
+	}
+	return availability, nil
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/mpppk/grouping/cmd/option"
+	"github.com/mpppk/grouping/domain"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+func newBoundsCmd(fs afero.Fs) (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:   "bounds",
+		Short: "show theoretical bounds of groups",
+		Long: `show the maximum number of rounds in which no pair of members meets twice,
+and the lower bound of number of duplicated member pairs for the number of rounds`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			conf, err := option.NewBoundsCmdConfigFromViper(args)
+			if err != nil {
+				return err
+			}
+
+			capacities := conf.GroupSizes
+			if len(capacities) == 0 {
+				capacities, err = domain.GroupCapacities(conf.MemberNum, conf.GroupSize)
+				if err != nil {
+					return fmt.Errorf("failed to decide group capacities: %w", err)
+				}
+			}
+
+			bounds, err := domain.NewBounds(capacities)
+			if err != nil {
+				return fmt.Errorf("failed to calculate bounds: %w", err)
+			}
+
+			cmd.Printf("group sizes: %s\n", formatIntList(capacities))
+			if bounds.Design != nil {
+				cmd.Printf("max repeat-free rounds: %d (achieved by %s)\n", bounds.MaxRepeatFreeRoundNum, bounds.Design.Name)
+			} else {
+				cmd.Printf("max repeat-free rounds: at most %d\n", bounds.MaxRepeatFreeRoundNum)
+			}
+			if conf.Rounds > 0 {
+				capacitiesList := make([][]int, conf.Rounds)
+				for i := range capacitiesList {
+					capacitiesList[i] = capacities
+				}
+				cmd.Printf("lower bound of duplicated member pairs in %d rounds: %d\n", conf.Rounds, domain.DupLowerBound(capacitiesList))
+			}
+			return nil
+		},
+	}
+
+	registerBoundsCommandFlags := func(cmd *cobra.Command) error {
+		flags := []option.Flag{
+			&option.IntFlag{
+				BaseFlag: &option.BaseFlag{
+					Name:      "member-num",
+					Usage:     "number of members",
+					ViperName: "bounds.memberNum",
+				},
+			},
+			&option.IntFlag{
+				BaseFlag: &option.BaseFlag{
+					Name:      "group-size",
+					Usage:     "number of members in each group",
+					ViperName: "bounds.groupSize",
+				},
+				Value: 4,
+			},
+			&option.StringFlag{
+				BaseFlag: &option.BaseFlag{
+					Name:      "group-sizes",
+					Usage:     "comma separated sizes of groups such as 4,4,3. group-size is ignored if specified",
+					ViperName: "bounds.groupSizes",
+				},
+			},
+			&option.IntFlag{
+				BaseFlag: &option.BaseFlag{
+					Name:      "rounds",
+					Usage:     "number of rounds to calculate lower bound of duplicated member pairs",
+					ViperName: "bounds.rounds",
+				},
+			},
+		}
+		return option.RegisterFlags(cmd, flags)
+	}
+
+	if err := registerBoundsCommandFlags(cmd); err != nil {
+		return nil, err
+	}
+
+	return cmd, nil
+}
+
+func formatIntList(list []int) (s string) {
+	for i, v := range list {
+		if i > 0 {
+			s += ","
+		}
+		s += fmt.Sprint(v)
+	}
+	return
+}
+
+func init() {
+	cmdGenerators = append(cmdGenerators, newBoundsCmd)
+}
@@ -0,0 +1,49 @@
+package cmd_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/mpppk/grouping/cmd"
+	"github.com/spf13/afero"
+)
+
+func TestBounds(t *testing.T) {
+	cases := []struct {
+		command string
+		want    string
+	}{
+		{
+			command: "bounds --member-num 16 --group-size 4 --rounds 6",
+			want:    "group sizes: 4,4,4,4\nmax repeat-free rounds: 5 (achieved by affine geometry AG(2,4))\nlower bound of duplicated member pairs in 6 rounds: 24\n",
+		},
+		{
+			command: "bounds --member-num 12 --group-size 4 --rounds 3",
+			want:    "group sizes: 4,4,4\nmax repeat-free rounds: at most 1\nlower bound of duplicated member pairs in 3 rounds: 6\n",
+		},
+		{
+			command: "bounds --group-sizes 3,3,2",
+			want:    "group sizes: 3,3,2\nmax repeat-free rounds: at most 4\n",
+		},
+	}
+
+	for _, c := range cases {
+		buf := new(bytes.Buffer)
+		rootCmd, err := cmd.NewRootCmd(afero.NewMemMapFs())
+		if err != nil {
+			t.Errorf("failed to create rootCmd: %s", err)
+		}
+		rootCmd.SetOut(buf)
+		cmdArgs := strings.Split(c.command, " ")
+		rootCmd.SetArgs(cmdArgs)
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("failed to execute rootCmd: %s", err)
+		}
+
+		get := buf.String()
+		if c.want != get {
+			t.Errorf("unexpected response: want:%q, get:%q", c.want, get)
+		}
+	}
+}
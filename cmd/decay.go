@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"github.com/mpppk/grouping/cmd/option"
+	"github.com/mpppk/grouping/domain"
+)
+
+func newDecayFlags(cmdName string) []option.Flag {
+	return []option.Flag{
+		&option.Float64Flag{
+			BaseFlag: &option.BaseFlag{
+				Name:      "half-life",
+				Usage:     "number of rounds in which penalty of meeting a member again halves (no decay if 0)",
+				ViperName: cmdName + ".halfLife",
+			},
+		},
+		&option.IntFlag{
+			BaseFlag: &option.BaseFlag{
+				Name:      "window",
+				Usage:     "number of rounds in which penalty of meeting a member again decreases linearly to 0 (no decay if 0)",
+				ViperName: cmdName + ".window",
+			},
+		},
+	}
+}
+
+// newDecay returns Decay from conf which has been validated, or nil if decay is disabled
+func newDecay(conf *option.DecayConfig) domain.Decay {
+	switch {
+	case conf.HalfLife > 0:
+		return &domain.ExponentialDecay{HalfLife: conf.HalfLife}
+	case conf.Window > 0:
+		return &domain.LinearDecay{Window: conf.Window}
+	}
+	return nil
+}
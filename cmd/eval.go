@@ -2,6 +2,13 @@ package cmd
 
 import (
 	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/mpppk/grouping/cmd/option"
 	"github.com/mpppk/grouping/domain"
@@ -20,17 +27,171 @@ func newEvalCmd(fs afero.Fs) (*cobra.Command, error) {
 				return err
 			}
 
-			groupsList, err := domain.ParseGroupFile(conf.File)
+			members, groupsList, err := domain.ParseGroupFileWithMembers(conf.File)
 			if err != nil {
 				return fmt.Errorf("failed to parse group file from %s: %w", conf.File, err)
 			}
 
+			// attributes and roles of members are used by constraints as well
+			if conf.Members != "" {
+				if err := copyAttributes(members, conf.Members); err != nil {
+					return err
+				}
+			}
+
 			cnt, err := domain.CountDupMemberPairs(groupsList)
 			if err != nil {
 				return fmt.Errorf("failed to count dup member pairs: %w", err)
 			}
 
-			cmd.Println(cnt)
+			// every result is written to stdout so that tables and their headings are not split
+			out := cmd.OutOrStdout()
+			fmt.Fprintln(out, cnt)
+
+			if conf.Details {
+				for _, pair := range domain.DupPairs(groupsList) {
+					fmt.Fprintln(out, pair)
+				}
+			}
+
+			s, err := domain.NewScheduleFromGroupsList(members, groupsList)
+			if err != nil {
+				return fmt.Errorf("failed to load groups: %w", err)
+			}
+
+			if decay := newDecay(&conf.DecayConfig); decay != nil {
+				fmt.Fprintf(out, "time-decayed duplicated member pairs: %.3f\n", domain.DecayedDupPenalty(s, decay))
+			}
+
+			if conf.Weights != "" {
+				weights, err := parsePairWeights(conf.Weights)
+				if err != nil {
+					return err
+				}
+				score, err := domain.WeightedScore(s, newDecay(&conf.DecayConfig), weights)
+				if err != nil {
+					return fmt.Errorf("failed to evaluate weighted score: %w", err)
+				}
+				fmt.Fprintf(out, "weighted score: %.3f\n", score)
+			}
+
+			if conf.ByMember {
+				fmt.Fprintln(out, "statistics of each member:")
+				if err := writeMemberStats(out, s.MemberStats()); err != nil {
+					return fmt.Errorf("failed to write statistics of members: %w", err)
+				}
+			}
+
+			if conf.Coverage {
+				pairNum := domain.PairNum(len(members))
+				cumulative := s.CumulativeMetPairs()
+				met := 0
+				if len(cumulative) > 0 {
+					met = cumulative[len(cumulative)-1]
+				}
+				fmt.Fprintf(out, "pair coverage: %s\n", formatCoverage(met, pairNum))
+				fmt.Fprintln(out, "cumulative pair coverage of each round:")
+				for r, met := range cumulative {
+					fmt.Fprintf(out, "round %d: %s\n", r+1, formatCoverage(met, pairNum))
+				}
+			}
+
+			if conf.Bound {
+				bound := s.DupLowerBound()
+				fmt.Fprintf(out, "lower bound: %d\ngap from lower bound: %d\n", bound, cnt-bound)
+			}
+
+			if conf.Constraints != "" {
+				constraints, err := domain.ParseConstraintFile(conf.Constraints)
+				if err != nil {
+					return fmt.Errorf("failed to parse constraint file from %s: %w", conf.Constraints, err)
+				}
+				violations := constraints.Violations(groupsList)
+				fmt.Fprintf(out, "constraint violations: %d\n", len(violations))
+				for _, v := range violations {
+					fmt.Fprintln(out, v)
+				}
+			}
+
+			if conf.MinGroupSize > 0 || conf.MaxGroupSize > 0 {
+				violations := domain.GroupSizeViolations(groupsList, conf.MinGroupSize, conf.MaxGroupSize)
+				fmt.Fprintf(out, "groups out of size bounds: %d\n", len(violations))
+				for _, v := range violations {
+					fmt.Fprintln(out, v)
+				}
+			}
+
+			// rating column is numeric, so it is evaluated by its spread below instead of imbalance
+			if names := domain.AttributeNames(members, conf.Rating); len(names) > 0 {
+				fmt.Fprintln(out, "attribute imbalance of each round (members outside of fair share, 0 is the most balanced):")
+				for _, name := range names {
+					imbalances, err := domain.AttributeImbalances(members, groupsList, name)
+					if err != nil {
+						return fmt.Errorf("failed to evaluate balance of %s: %w", name, err)
+					}
+					fmt.Fprintf(out, "%s: %s\n", name, formatIntList(imbalances))
+				}
+			}
+
+			if conf.Rating != "" {
+				ratingsList, err := domain.GroupRatings(members, groupsList, conf.Rating, conf.RatingAverage)
+				if err != nil {
+					return fmt.Errorf("failed to evaluate ratings of %s: %w", conf.Rating, err)
+				}
+				kind := "total"
+				if conf.RatingAverage {
+					kind = "average"
+				}
+				fmt.Fprintf(out, "%s %s of each group (spread is the highest minus the lowest):\n", kind, conf.Rating)
+				for r, ratings := range ratingsList {
+					fmt.Fprintf(out, "round %d: %s (spread %s)\n", r+1, formatFloatList(ratings), formatFloat(domain.RatingSpread(ratings)))
+				}
+			}
+
+			if conf.Preferences != "" {
+				preferences, err := parsePreferences(conf.Preferences)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(out, "satisfied preferences of each member:")
+				for _, satisfaction := range domain.PreferenceSatisfactions(groupsList, preferences) {
+					wanted := len(satisfaction.Met) + len(satisfaction.NotMet)
+					if len(satisfaction.NotMet) == 0 {
+						fmt.Fprintf(out, "%s: %d/%d\n", satisfaction.Name, len(satisfaction.Met), wanted)
+						continue
+					}
+					fmt.Fprintf(out, "%s: %d/%d (not met: %s)\n", satisfaction.Name, len(satisfaction.Met), wanted, strings.Join(satisfaction.NotMet, ", "))
+				}
+			}
+
+			if conf.Breakdown {
+				objective, err := newObjective(&conf.ObjectiveConfig)
+				if err != nil {
+					return err
+				}
+				terms, err := objective.Breakdown(s)
+				if err != nil {
+					return fmt.Errorf("failed to evaluate objective: %w", err)
+				}
+				fmt.Fprintln(out, "objective breakdown:")
+				if err := writeBreakdown(out, terms); err != nil {
+					return fmt.Errorf("failed to write objective breakdown: %w", err)
+				}
+			}
+
+			if conf.Optimal {
+				rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+				result, err := domain.FindOptimalSchedule(cmd.Context(), s, domain.NewMultiStartSolver(domain.NewAnnealingSolver(200000), 0, 0), conf.MaxNodes, rnd)
+				if err != nil {
+					return fmt.Errorf("failed to find optimal groups: %w", err)
+				}
+				if result.Optimal() {
+					fmt.Fprintf(out, "optimum: %d\ngap: %d\n", result.Dup, cnt-result.Dup)
+				} else {
+					fmt.Fprintf(out, "optimum: between %d and %d\ngap: at most %d\n", result.LowerBound, result.Dup, cnt-result.LowerBound)
+				}
+				fmt.Fprintf(out, "proof: %s\n", result.Proof())
+			}
 
 			return nil
 		},
@@ -44,6 +205,165 @@ func newEvalCmd(fs afero.Fs) (*cobra.Command, error) {
 					Usage: "file",
 				},
 			},
+			&option.BoolFlag{
+				BaseFlag: &option.BaseFlag{
+					Name:  "details",
+					Usage: "show every duplicated member pair with rounds and groups where they meet, in descending order of number of meetings",
+				},
+			},
+			&option.BoolFlag{
+				BaseFlag: &option.BaseFlag{
+					Name:      "by-member",
+					Usage:     "show number of rounds attended, distinct members met and repeated meetings of each member, and members whom they have never met",
+					ViperName: "byMember",
+				},
+			},
+			&option.BoolFlag{
+				BaseFlag: &option.BaseFlag{
+					Name:  "coverage",
+					Usage: "show fraction of all possible member pairs who have met, and its cumulative value at the end of each round",
+				},
+			},
+			&option.BoolFlag{
+				BaseFlag: &option.BaseFlag{
+					Name:  "bound",
+					Usage: "show gap between number of duplicated member pairs and its theoretical lower bound",
+				},
+			},
+			&option.BoolFlag{
+				BaseFlag: &option.BaseFlag{
+					Name:  "optimal",
+					Usage: "show gap between the groups and optimal groups which have same members and group sizes",
+				},
+			},
+			&option.StringFlag{
+				BaseFlag: &option.BaseFlag{
+					Name:  "constraints",
+					Usage: "constraint csv file. violations of the constraints are shown",
+				},
+				IsFileName: true,
+			},
+			&option.StringFlag{
+				BaseFlag: &option.BaseFlag{
+					Name:  "weights",
+					Usage: "pair weight csv file. score in which each meeting of the pairs costs the weight is shown",
+				},
+				IsFileName: true,
+			},
+			&option.BoolFlag{
+				BaseFlag: &option.BaseFlag{
+					Name:  "breakdown",
+					Usage: "show contribution of each term to score of the objective which generators minimize",
+				},
+			},
+			&option.StringFlag{
+				BaseFlag: &option.BaseFlag{
+					Name:  "balance",
+					Usage: "comma separated attribute columns of member file which are balanced in the objective of --breakdown",
+				},
+			},
+			&option.StringFlag{
+				BaseFlag: &option.BaseFlag{
+					Name:  "rating",
+					Usage: "numeric attribute column of member file. total rating of each group and their spread are shown",
+				},
+			},
+			&option.BoolFlag{
+				BaseFlag: &option.BaseFlag{
+					Name:      "rating-average",
+					Usage:     "show average ratings of groups instead of totals",
+					ViperName: "ratingAverage",
+				},
+			},
+			&option.Float64Flag{
+				BaseFlag: &option.BaseFlag{
+					Name:      "rating-weight",
+					Usage:     "weight of variance of group ratings in the objective of --breakdown",
+					ViperName: "ratingWeight",
+				},
+				Value: 1,
+			},
+			&option.Float64Flag{
+				BaseFlag: &option.BaseFlag{
+					Name:      "dup-weight",
+					Usage:     "weight of duplicated member pairs in the objective of --breakdown",
+					ViperName: "dupWeight",
+				},
+				Value: 1,
+			},
+			&option.Float64Flag{
+				BaseFlag: &option.BaseFlag{
+					Name:      "balance-weight",
+					Usage:     "weight of attribute imbalance in the objective of --breakdown",
+					ViperName: "balanceWeight",
+				},
+				Value: 1,
+			},
+			&option.Float64Flag{
+				BaseFlag: &option.BaseFlag{
+					Name:      "soft-weight",
+					Usage:     "weight of violations of soft constraints in the objective of --breakdown",
+					ViperName: "softWeight",
+				},
+				Value: 1,
+			},
+			&option.StringFlag{
+				BaseFlag: &option.BaseFlag{
+					Name:  "preferences",
+					Usage: "preference csv file. number of satisfied preferences of each member is shown",
+				},
+				IsFileName: true,
+			},
+			&option.Float64Flag{
+				BaseFlag: &option.BaseFlag{
+					Name:      "preference-weight",
+					Usage:     "weight of satisfied preferences in the objective of --breakdown",
+					ViperName: "preferenceWeight",
+				},
+				Value: 1,
+			},
+			&option.StringFlag{
+				BaseFlag: &option.BaseFlag{
+					Name:  "members",
+					Usage: "member csv file. balance of each attribute column is shown, and roles are used by constraints",
+				},
+				IsFileName: true,
+			},
+			&option.IntFlag{
+				BaseFlag: &option.BaseFlag{
+					Name:      "min-group-size",
+					Usage:     "show groups which have fewer members than this",
+					ViperName: "minGroupSize",
+				},
+			},
+			&option.IntFlag{
+				BaseFlag: &option.BaseFlag{
+					Name:      "max-group-size",
+					Usage:     "show groups which have more members than this",
+					ViperName: "maxGroupSize",
+				},
+			},
+			&option.Float64Flag{
+				BaseFlag: &option.BaseFlag{
+					Name:      "half-life",
+					Usage:     "show duplicated member pairs whose penalty halves in this number of rounds since the last meeting",
+					ViperName: "halfLife",
+				},
+			},
+			&option.IntFlag{
+				BaseFlag: &option.BaseFlag{
+					Name:  "window",
+					Usage: "show duplicated member pairs whose penalty decreases linearly to 0 in this number of rounds since the last meeting",
+				},
+			},
+			&option.IntFlag{
+				BaseFlag: &option.BaseFlag{
+					Name:      "max-nodes",
+					Usage:     "maximum number of nodes which exact solver explores",
+					ViperName: "maxNodes",
+				},
+				Value: 50000000,
+			},
 		}
 		return option.RegisterFlags(cmd, flags)
 	}
@@ -55,6 +375,53 @@ func newEvalCmd(fs afero.Fs) (*cobra.Command, error) {
 	return cmd, nil
 }
 
+// writeMemberStats writes stats of members as a table
+func writeMemberStats(out io.Writer, stats []*domain.MemberStats) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "member\trounds\tmet\trepeats\tnever met")
+	for _, st := range stats {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%s\n", st.Name, st.Rounds, st.Met, st.Repeats, strings.Join(st.NeverMet, ","))
+	}
+	return w.Flush()
+}
+
+// formatCoverage returns met pairs out of pairNum pairs and its percentage
+func formatCoverage(met, pairNum int) string {
+	if pairNum == 0 {
+		return fmt.Sprintf("%d/%d", met, pairNum)
+	}
+	return fmt.Sprintf("%d/%d (%.1f%%)", met, pairNum, float64(met)*100/float64(pairNum))
+}
+
+// formatFloatList returns comma separated list of values rounded by formatFloat
+func formatFloatList(list []float64) (s string) {
+	for i, v := range list {
+		if i > 0 {
+			s += ","
+		}
+		s += formatFloat(v)
+	}
+	return
+}
+
+// formatFloat returns v rounded to 3 decimal places without trailing zeros
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(math.Round(v*1000)/1000, 'f', -1, 64)
+}
+
+// writeBreakdown writes terms of the objective and their total as a table
+func writeBreakdown(out io.Writer, terms []*domain.ObjectiveTerm) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "term\tvalue\tweight\tscore")
+	total := 0.0
+	for _, term := range terms {
+		fmt.Fprintf(w, "%s\t%.3f\t%g\t%.3f\n", term.Name, term.Value, term.Weight, term.Score())
+		total += term.Score()
+	}
+	fmt.Fprintf(w, "total\t\t\t%.3f\n", total)
+	return w.Flush()
+}
+
 func init() {
 	cmdGenerators = append(cmdGenerators, newEvalCmd)
 }
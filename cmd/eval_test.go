@@ -16,15 +16,119 @@ func TestEval(t *testing.T) {
 	}{
 		{command: "eval --file ../testdata/dup_groups.csv", want: "2\n"},
 		{command: "eval --file ../testdata/no_dup_groups.csv", want: "0\n"},
+		{command: "eval --file ../testdata/commented_groups.csv", want: "0\n"},
+		{command: "eval --file ../testdata/absent_groups.csv", want: "1\n"},
+		{
+			command: "eval --file ../testdata/repeated_groups.csv --coverage",
+			want: "2\npair coverage: 4/6 (66.7%)\n" +
+				"cumulative pair coverage of each round:\n" +
+				"round 1: 2/6 (33.3%)\n" +
+				"round 2: 4/6 (66.7%)\n" +
+				"round 3: 4/6 (66.7%)\n",
+		},
+		{
+			command: "eval --file ../testdata/absent_groups.csv --by-member",
+			want: "1\nstatistics of each member:\n" +
+				"member  rounds  met  repeats  never met\n" +
+				"alice   1       1    0        carol,dave,ellen\n" +
+				"bob     2       2    0        carol,dave\n" +
+				"carol   2       1    1        alice,bob,ellen\n" +
+				"dave    2       1    1        alice,bob,ellen\n" +
+				"ellen   1       1    0        alice,carol,dave\n",
+		},
+		{
+			command: "eval --file ../testdata/repeated_groups.csv --details",
+			want: "2\n" +
+				"alice and bob: 2 times (round 1, group 1; round 3, group 1)\n" +
+				"carol and dave: 2 times (round 1, group 2; round 3, group 2)\n",
+		},
+		{command: "eval --file ../testdata/repeated_groups.csv --half-life 1", want: "2\ntime-decayed duplicated member pairs: 1.000\n"},
+		{command: "eval --file ../testdata/repeated_groups.csv --window 3", want: "2\ntime-decayed duplicated member pairs: 1.333\n"},
+		{command: "eval --file ../testdata/dup_groups.csv --weights ../testdata/pair_weights.csv", want: "2\nweighted score: 4.000\n"},
+		{command: "eval --file ../testdata/dup_groups.csv --bound", want: "2\nlower bound: 0\ngap from lower bound: 2\n"},
+		{command: "eval --file ../testdata/late_joiners_groups.csv --bound", want: "2\nlower bound: 2\ngap from lower bound: 0\n"},
+		{
+			command: "eval --file ../testdata/dup_groups.csv --constraints ../testdata/constraints.csv",
+			want: "2\nconstraint violations: 4\n" +
+				"round 1, group 1: alice and carol must be together (carol is in group 2)\n" +
+				"round 1, group 1: alice and bob must be apart (bob is in group 1)\n" +
+				"round 2, group 2: alice and carol must be together (carol is in group 1)\n" +
+				"round 2, group 2: alice and bob must be apart (bob is in group 2)\n",
+		},
+		{
+			command: "eval --file ../testdata/dup_groups.csv --constraints ../testdata/soft_constraints.csv --breakdown --dup-weight 0.5",
+			want: "2\nconstraint violations: 4\n" +
+				"round 1, group 1: alice and carol must be together (carol is in group 2) (soft, weight 2)\n" +
+				"round 1, group 1: alice and bob must be apart (bob is in group 1)\n" +
+				"round 2, group 2: alice and carol must be together (carol is in group 1) (soft, weight 2)\n" +
+				"round 2, group 2: alice and bob must be apart (bob is in group 2)\n" +
+				"objective breakdown:\n" +
+				"term                        value  weight  score\n" +
+				"duplicated member pairs     2.000  0.5     1.000\n" +
+				"hard constraint violations  2.000  11      22.000\n" +
+				"soft constraint violations  4.000  1       4.000\n" +
+				"total                                      27.000\n",
+		},
+		{
+			command: "eval --file ../testdata/no_dup_groups.csv --constraints ../testdata/role_constraints.csv --members ../testdata/members_with_roles.csv",
+			want: "0\nconstraint violations: 2\n" +
+				"round 2, group 2: each group must have at least 1 facilitator (group has 0)\n" +
+				"round 2, group 2: each group must have at most 1 manager (group has 2)\n",
+		},
+		{
+			command: "eval --file ../testdata/no_dup_groups.csv --members ../testdata/members_with_ratings.csv --rating skill --breakdown",
+			want: "0\n" +
+				"total skill of each group (spread is the highest minus the lowest):\n" +
+				"round 1: 8,5.5 (spread 2.5)\n" +
+				"round 2: 9,4.5 (spread 4.5)\n" +
+				"objective breakdown:\n" +
+				"term                     value  weight  score\n" +
+				"duplicated member pairs  0.000  1       0.000\n" +
+				"rating variance          6.625  1       6.625\n" +
+				"total                                   6.625\n",
+		},
+		{
+			command: "eval --file ../testdata/no_dup_groups.csv --members ../testdata/members_with_ratings.csv --rating skill --rating-average",
+			want: "0\n" +
+				"average skill of each group (spread is the highest minus the lowest):\n" +
+				"round 1: 4,2.75 (spread 1.25)\n" +
+				"round 2: 4.5,2.25 (spread 2.25)\n",
+		},
+		{
+			command: "eval --file ../testdata/no_dup_groups.csv --preferences ../testdata/preferences.csv --breakdown",
+			want: "0\nsatisfied preferences of each member:\n" +
+				"alice: 2/3 (not met: dave)\n" +
+				"dave: 1/1\n" +
+				"objective breakdown:\n" +
+				"term                     value   weight  score\n" +
+				"duplicated member pairs  0.000   1       0.000\n" +
+				"satisfied preferences    -1.833  1       -1.833\n" +
+				"total                                    -1.833\n",
+		},
+		{
+			command: "eval --file ../testdata/no_dup_groups.csv --members ../testdata/members_with_attributes.csv",
+			want:    "0\nattribute imbalance of each round (members outside of fair share, 0 is the most balanced):\ndepartment: 0,0\nlocation: 0,4\n",
+		},
+		{
+			command: "eval --file ../testdata/uneven_groups.csv --min-group-size 2 --max-group-size 2",
+			want: "0\ngroups out of size bounds: 2\n" +
+				"round 1, group 1: size 3 is larger than max group size 2\n" +
+				"round 2, group 3: size 1 is smaller than min group size 2\n",
+		},
+		{
+			command: "eval --file ../testdata/dup_groups.csv --optimal",
+			want:    "2\noptimum: 0\ngap: 2\nproof: every member meets at least as many members as possible, so the counting bound is attained\n",
+		},
 	}
 
 	for _, c := range cases {
-		buf := new(bytes.Buffer)
+		buf, errBuf := new(bytes.Buffer), new(bytes.Buffer)
 		rootCmd, err := cmd.NewRootCmd(afero.NewMemMapFs())
 		if err != nil {
 			t.Errorf("failed to create rootCmd: %s", err)
 		}
 		rootCmd.SetOut(buf)
+		rootCmd.SetErr(errBuf)
 		cmdArgs := strings.Split(c.command, " ")
 		rootCmd.SetArgs(cmdArgs)
 		if err := rootCmd.Execute(); err != nil {
@@ -35,5 +139,9 @@ func TestEval(t *testing.T) {
 		if c.want != get {
 			t.Errorf("unexpected response: want:%q, get:%q", c.want, get)
 		}
+		// results must not be split between stdout and stderr
+		if errBuf.Len() > 0 {
+			t.Errorf("unexpected output to stderr: %q", errBuf.String())
+		}
 	}
 }
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/mpppk/grouping/cmd/option"
+	"github.com/mpppk/grouping/domain"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+func newGenerateCmd(fs afero.Fs) (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "generate groups",
+		Long:  `generate groups of each round so that members meet as few duplicated members as possible`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			conf, err := option.NewGenerateCmdConfigFromViper(args)
+			if err != nil {
+				return err
+			}
+
+			members, err := domain.ParseMemberFile(conf.Members)
+			if err != nil {
+				return fmt.Errorf("failed to parse member file from %s: %w", conf.Members, err)
+			}
+
+			sizes := newGroupSizes(&conf.GroupSizeConfig)
+			availability, err := parseAvailability(conf.Availability)
+			if err != nil {
+				return err
+			}
+			objective, err := newObjective(&conf.ObjectiveConfig)
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := newSolverContext(cmd.Context(), &conf.SolverConfig)
+			defer cancel()
+			solver, rnd := newSolver(&conf.SolverConfig), newRand(&conf.SolverConfig)
+			var groupsList []domain.Groups
+			if conf.Strategy == option.StrategyExact {
+				// exact strategy ignores availability and objective
+				if err := logDesignRounds(members, nil, conf.Rounds, sizes, nil); err != nil {
+					return err
+				}
+				result, err := domain.GenerateOptimalGroups(ctx, members, conf.Rounds, sizes, conf.Trials, solver, conf.MaxNodes, rnd)
+				if err != nil {
+					return fmt.Errorf("failed to generate optimal groups: %w", err)
+				}
+				if err := printExactResult(cmd.ErrOrStderr(), result); err != nil {
+					return fmt.Errorf("failed to write result of exact solver: %w", err)
+				}
+				groupsList = result.Schedule.GroupsList()
+			} else {
+				if err := logDesignRounds(members, availability, conf.Rounds, sizes, objective); err != nil {
+					return err
+				}
+				groupsList, err = domain.GenerateGroups(ctx, members, availability, conf.Rounds, sizes, conf.Trials, objective, solver, rnd)
+				if err != nil {
+					return fmt.Errorf("failed to generate groups: %w", err)
+				}
+			}
+
+			comments := []string{reproductionComment(cmd, &conf.SolverConfig)}
+			if err := domain.WriteGroupFile(cmd.OutOrStdout(), comments, nil, members, groupsList); err != nil {
+				return fmt.Errorf("failed to write group file: %w", err)
+			}
+			return nil
+		},
+	}
+
+	registerGenerateCommandFlags := func(cmd *cobra.Command) error {
+		flags := []option.Flag{
+			&option.StringFlag{
+				BaseFlag: &option.BaseFlag{
+					Name:      "members",
+					Usage:     "member csv file which has ID and NAME columns. other columns are attributes of members",
+					ViperName: "generate.members",
+				},
+				IsFileName: true,
+			},
+			&option.IntFlag{
+				BaseFlag: &option.BaseFlag{
+					Name:      "rounds",
+					Usage:     "number of rounds",
+					ViperName: "generate.rounds",
+				},
+				Value: 1,
+			},
+			&option.StringFlag{
+				BaseFlag: &option.BaseFlag{
+					Name:      "availability",
+					Usage:     "availability csv file which has NAME column and a column for each round. absent, no, x, 0 or empty cell means that the member is absent from the round",
+					ViperName: "generate.availability",
+				},
+				IsFileName: true,
+			},
+			&option.IntFlag{
+				BaseFlag: &option.BaseFlag{
+					Name:      "trials",
+					Usage:     "number of attempts to generate groups",
+					ViperName: "generate.trials",
+				},
+				Value: 100,
+			},
+		}
+		flags = append(flags, newGroupSizeFlags("generate")...)
+		flags = append(flags, newObjectiveFlags("generate")...)
+		flags = append(flags, newSolverFlags("generate")...)
+		return option.RegisterFlags(cmd, flags)
+	}
+
+	if err := registerGenerateCommandFlags(cmd); err != nil {
+		return nil, err
+	}
+
+	return cmd, nil
+}
+
+// logDesignRounds logs the known design and number of rounds which are built from it
+func logDesignRounds(members []*domain.Member, availability *domain.Availability, roundNum int, sizes *domain.GroupSizes, objective *domain.Objective) error {
+	design, designRoundNum, err := domain.DesignRounds(members, availability, roundNum, sizes, objective)
+	if err != nil {
+		return err
+	}
+	if designRoundNum > 0 {
+		log.Printf("info: %s is used for the first %d rounds", design.Name, designRoundNum)
+	}
+	return nil
+}
+
+func init() {
+	cmdGenerators = append(cmdGenerators, newGenerateCmd)
+}
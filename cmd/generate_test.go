@@ -0,0 +1,377 @@
+package cmd_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/mpppk/grouping/cmd"
+	"github.com/spf13/afero"
+)
+
+func TestGenerate(t *testing.T) {
+	cases := []struct {
+		command     string
+		wantHeaders []string
+		wantRows    int
+	}{
+		{
+			command:     "generate --members ../testdata/members.csv --rounds 3 --group-size 4",
+			wantHeaders: []string{"NAME", "1st", "2nd", "3rd"},
+			wantRows:    8,
+		},
+		{
+			command:     "generate --members ../testdata/members.csv --rounds 2 --time-limit 100ms --iterations 1000",
+			wantHeaders: []string{"NAME", "1st", "2nd"},
+			wantRows:    8,
+		},
+		{
+			command:     "generate --members ../testdata/members.csv --rounds 3 --group-size 2 --strategy tabu --tabu-iterations 100",
+			wantHeaders: []string{"NAME", "1st", "2nd", "3rd"},
+			wantRows:    8,
+		},
+		{
+			command:     "generate --members ../testdata/members.csv --rounds 3 --group-size 2 --strategy genetic --population 10 --generations 20",
+			wantHeaders: []string{"NAME", "1st", "2nd", "3rd"},
+			wantRows:    8,
+		},
+		{
+			command:     "generate --members ../testdata/members.csv",
+			wantHeaders: []string{"NAME", "1st"},
+			wantRows:    8,
+		},
+	}
+
+	for _, c := range cases {
+		buf := new(bytes.Buffer)
+		rootCmd, err := cmd.NewRootCmd(afero.NewMemMapFs())
+		if err != nil {
+			t.Errorf("failed to create rootCmd: %s", err)
+		}
+		rootCmd.SetOut(buf)
+		cmdArgs := strings.Split(c.command, " ")
+		rootCmd.SetArgs(cmdArgs)
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("failed to execute rootCmd: %s", err)
+		}
+
+		if !strings.HasPrefix(buf.String(), "# grouping generate ") {
+			t.Errorf("generated csv must start with command line comment: %q", buf.String())
+		}
+		reader := csv.NewReader(buf)
+		reader.Comment = '#'
+		lines, err := reader.ReadAll()
+		if err != nil {
+			t.Fatalf("failed to read generated csv: %s", err)
+		}
+		if strings.Join(lines[0], ",") != strings.Join(c.wantHeaders, ",") {
+			t.Errorf("unexpected headers: want:%q, get:%q", c.wantHeaders, lines[0])
+		}
+		if len(lines)-1 != c.wantRows {
+			t.Errorf("unexpected row num: want:%d, get:%d", c.wantRows, len(lines)-1)
+		}
+	}
+}
+
+func TestGenerate_Seed(t *testing.T) {
+	execute := func(command string) string {
+		buf := new(bytes.Buffer)
+		rootCmd, err := cmd.NewRootCmd(afero.NewMemMapFs())
+		if err != nil {
+			t.Fatalf("failed to create rootCmd: %s", err)
+		}
+		rootCmd.SetOut(buf)
+		rootCmd.SetArgs(strings.Split(command, " "))
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("failed to execute rootCmd: %s", err)
+		}
+		return buf.String()
+	}
+
+	command := "generate --members ../testdata/members.csv --rounds 3 --group-size 3 --iterations 1000 --starts 2"
+	first := execute(command + " --seed 42")
+	if second := execute(command + " --seed 42"); first != second {
+		t.Errorf("same seed must generate same groups: first:%q, second:%q", first, second)
+	}
+	if !strings.Contains(first, "--seed 42") {
+		t.Errorf("seed must be recorded in output: %q", first)
+	}
+
+	// number of starts is recorded as a resolved value, because its default depends on GOMAXPROCS
+	comment := strings.SplitN(execute("generate --members ../testdata/members.csv --rounds 2 --iterations 100"), "\n", 2)[0] + " "
+	if strings.Contains(comment, "--starts 0 ") || strings.Contains(comment, "--workers 0 ") {
+		t.Errorf("starts and workers must be resolved in output: %q", comment)
+	}
+
+	// the recorded command line reproduces the output even if seed is not specified
+	generated := execute(command)
+	recorded := strings.TrimPrefix(strings.SplitN(generated, "\n", 2)[0], "# grouping ")
+	if reproduced := execute(recorded); generated != reproduced {
+		t.Errorf("recorded command must reproduce the output: generated:%q, reproduced:%q", generated, reproduced)
+	}
+}
+
+func TestGenerate_ExactTimeLimit(t *testing.T) {
+	out, errOut := new(bytes.Buffer), new(bytes.Buffer)
+	rootCmd, err := cmd.NewRootCmd(afero.NewMemMapFs())
+	if err != nil {
+		t.Fatalf("failed to create rootCmd: %s", err)
+	}
+	rootCmd.SetOut(out)
+	rootCmd.SetErr(errOut)
+	rootCmd.SetArgs(strings.Split("generate --members ../testdata/members.csv --rounds 3 --group-size 3 --strategy exact --time-limit 1s --workers 2", " "))
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("failed to execute rootCmd: %s", err)
+	}
+	// annealing must not repeat starts until time limit, so that exact search has its own time
+	if comment := strings.SplitN(out.String(), "\n", 2)[0] + " "; !strings.Contains(comment, "--starts 2 ") {
+		t.Errorf("exact strategy must use fixed number of starts: %q", comment)
+	}
+	if !strings.Contains(errOut.String(), "optimal: true") {
+		t.Errorf("exact strategy must prove optimality within time limit: %q", errOut.String())
+	}
+}
+
+func TestGenerate_Partial(t *testing.T) {
+	buf := new(bytes.Buffer)
+	rootCmd, err := cmd.NewRootCmd(afero.NewMemMapFs())
+	if err != nil {
+		t.Fatalf("failed to create rootCmd: %s", err)
+	}
+	rootCmd.SetOut(buf)
+	rootCmd.SetArgs(strings.Split("generate --members ../testdata/members.csv --rounds 3 --group-size 4 --partial ../testdata/partial_groups.csv", " "))
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("failed to execute rootCmd: %s", err)
+	}
+
+	reader := csv.NewReader(buf)
+	reader.Comment = '#'
+	lines, err := reader.ReadAll()
+	if err != nil {
+		t.Fatalf("failed to read generated csv: %s", err)
+	}
+	got := map[string][]string{}
+	for _, line := range lines[1:] {
+		got[line[0]] = line[1:]
+	}
+	pins := []struct {
+		name  string
+		round int
+		id    string
+	}{
+		{name: "alice", round: 0, id: "2"},
+		{name: "bob", round: 0, id: "2"},
+		{name: "heidi", round: 0, id: "1"},
+		{name: "bob", round: 1, id: "1"},
+		{name: "carol", round: 1, id: "1"},
+	}
+	for _, pin := range pins {
+		if id := got[pin.name][pin.round]; id != pin.id {
+			t.Errorf("%s must be pinned to group %s in round %d: got %s", pin.name, pin.id, pin.round+1, id)
+		}
+	}
+}
+
+func TestGenerate_InvalidPartial(t *testing.T) {
+	rootCmd, err := cmd.NewRootCmd(afero.NewMemMapFs())
+	if err != nil {
+		t.Fatalf("failed to create rootCmd: %s", err)
+	}
+	rootCmd.SetOut(new(bytes.Buffer))
+	rootCmd.SetErr(new(bytes.Buffer))
+	rootCmd.SetArgs(strings.Split("generate --members ../testdata/members.csv --rounds 2 --group-size 4 --partial ../testdata/invalid_partial_groups.csv", " "))
+	if err := rootCmd.Execute(); err == nil || !strings.Contains(err.Error(), "bob in round 1") {
+		t.Errorf("generate must fail if partial group file has non-positive group ID: %v", err)
+	}
+}
+
+func TestGenerate_Objective(t *testing.T) {
+	// sameGroup returns true if a and b are in the same group in round r
+	sameGroup := func(rows map[string][]string, a, b string, r int) bool {
+		return rows[a][r] != "" && rows[a][r] == rows[b][r]
+	}
+	// countIn returns number of names in each group of round r. Groups which have none of names are counted as 0
+	countIn := func(rows map[string][]string, r int, names ...string) map[string]int {
+		counts := map[string]int{}
+		for name := range rows {
+			if rows[name][r] != "" {
+				counts[rows[name][r]] += 0
+			}
+		}
+		for _, name := range names {
+			if rows[name][r] != "" {
+				counts[rows[name][r]]++
+			}
+		}
+		return counts
+	}
+	groupSizes := func(rows map[string][]string, r int) (sizes []int) {
+		var names []string
+		for name := range rows {
+			names = append(names, name)
+		}
+		counts := countIn(rows, r, names...)
+		for _, size := range counts {
+			sizes = append(sizes, size)
+		}
+		sort.Ints(sizes)
+		return
+	}
+
+	cases := []struct {
+		command string
+		check   func(t *testing.T, rows map[string][]string, roundNum int)
+	}{
+		{
+			command: "generate --members ../testdata/members.csv --rounds 3 --group-size 4 --constraints ../testdata/constraints.csv",
+			check: func(t *testing.T, rows map[string][]string, roundNum int) {
+				for r := 0; r < roundNum; r++ {
+					if !sameGroup(rows, "alice", "carol", r) || sameGroup(rows, "alice", "bob", r) {
+						t.Errorf("round %d violates constraints: %v", r+1, rows)
+					}
+				}
+			},
+		},
+		{
+			command: "generate --members ../testdata/members_with_roles.csv --rounds 2 --group-size 2 --constraints ../testdata/role_constraints.csv",
+			check: func(t *testing.T, rows map[string][]string, roundNum int) {
+				for r := 0; r < roundNum; r++ {
+					for id, count := range countIn(rows, r, "alice", "carol", "ellen", "grace") {
+						if count < 1 {
+							t.Errorf("group %s of round %d has no facilitator: %v", id, r+1, rows)
+						}
+					}
+					for id, count := range countIn(rows, r, "bob", "dave", "frank") {
+						if count > 1 {
+							t.Errorf("group %s of round %d has %d managers: %v", id, r+1, count, rows)
+						}
+					}
+				}
+			},
+		},
+		{
+			command: "generate --members ../testdata/members_with_attributes.csv --rounds 3 --group-size 4 --balance department,location --balance-weight 10",
+			check: func(t *testing.T, rows map[string][]string, roundNum int) {
+				for r := 0; r < roundNum; r++ {
+					for _, names := range [][]string{{"alice", "bob", "carol", "dave"}, {"alice", "carol", "ellen", "grace"}} {
+						for id, count := range countIn(rows, r, names...) {
+							if count != 2 {
+								t.Errorf("group %s of round %d has %d of %v: %v", id, r+1, count, names, rows)
+							}
+						}
+					}
+				}
+			},
+		},
+		{
+			command: "generate --members ../testdata/members.csv --rounds 3 --group-size 4 --weights ../testdata/pair_weights.csv",
+			check: func(t *testing.T, rows map[string][]string, roundNum int) {
+				// alice and bob cost 3 for a meeting, and carol and dave are rewarded for repeated meetings
+				aliceBob, carolDave := 0, 0
+				for r := 0; r < roundNum; r++ {
+					if sameGroup(rows, "alice", "bob", r) {
+						aliceBob++
+					}
+					if sameGroup(rows, "carol", "dave", r) {
+						carolDave++
+					}
+				}
+				if aliceBob > 1 || carolDave < 2 {
+					t.Errorf("alice and bob must meet at most once, and carol and dave more than once: %v", rows)
+				}
+			},
+		},
+		{
+			command: "generate --members ../testdata/members_with_attributes.csv --rounds 3 --group-size 4 --constraints ../testdata/soft_constraints.csv --balance department --balance-weight 2 --soft-weight 0.5",
+			check: func(t *testing.T, rows map[string][]string, roundNum int) {
+				for r := 0; r < roundNum; r++ {
+					if sameGroup(rows, "alice", "bob", r) {
+						t.Errorf("round %d violates hard constraint: %v", r+1, rows)
+					}
+				}
+			},
+		},
+		{
+			command: "generate --members ../testdata/members.csv --rounds 2 --group-size 4 --preferences ../testdata/preferences.csv --preference-weight 2",
+			check: func(t *testing.T, rows map[string][]string, roundNum int) {
+				for _, pair := range [][2]string{{"alice", "dave"}, {"alice", "carol"}, {"alice", "bob"}, {"dave", "carol"}} {
+					met := false
+					for r := 0; r < roundNum; r++ {
+						met = met || sameGroup(rows, pair[0], pair[1], r)
+					}
+					if !met {
+						t.Errorf("%s must meet %s: %v", pair[0], pair[1], rows)
+					}
+				}
+			},
+		},
+		{
+			command: "generate --members ../testdata/members_with_ratings.csv --rounds 2 --group-size 2 --rating skill --rating-weight 0.5",
+			check: func(t *testing.T, rows map[string][]string, roundNum int) {
+				// alice(5) with dave(1.5) and bob(3) with carol(4) is the most balanced split
+				if !sameGroup(rows, "alice", "dave", 0) && !sameGroup(rows, "alice", "dave", 1) {
+					t.Errorf("alice must be paired with dave in a round: %v", rows)
+				}
+			},
+		},
+		{
+			command: "generate --members ../testdata/members.csv --rounds 2 --group-sizes 3,3,2/5,3",
+			check: func(t *testing.T, rows map[string][]string, roundNum int) {
+				if got := []string{fmt.Sprint(groupSizes(rows, 0)), fmt.Sprint(groupSizes(rows, 1))}; got[0] != "[2 3 3]" || got[1] != "[3 5]" {
+					t.Errorf("unexpected group sizes: %v", got)
+				}
+			},
+		},
+		{
+			command: "generate --members ../testdata/members.csv --rounds 2 --group-size 4 --min-group-size 2 --max-group-size 3",
+			check: func(t *testing.T, rows map[string][]string, roundNum int) {
+				for r := 0; r < roundNum; r++ {
+					for _, size := range groupSizes(rows, r) {
+						if size < 2 || size > 3 {
+							t.Errorf("size of a group of round %d is out of bounds: %v", r+1, groupSizes(rows, r))
+						}
+					}
+				}
+			},
+		},
+		{
+			command: "generate --members ../testdata/members.csv --rounds 2 --group-size 3 --availability ../testdata/availability.csv",
+			check: func(t *testing.T, rows map[string][]string, roundNum int) {
+				if rows["alice"][1] != "" || rows["carol"][0] != "" || rows["alice"][0] == "" || rows["carol"][1] == "" {
+					t.Errorf("groups must follow availability: %v", rows)
+				}
+			},
+		},
+	}
+
+	for _, c := range cases {
+		buf := new(bytes.Buffer)
+		rootCmd, err := cmd.NewRootCmd(afero.NewMemMapFs())
+		if err != nil {
+			t.Fatalf("failed to create rootCmd: %s", err)
+		}
+		rootCmd.SetOut(buf)
+		rootCmd.SetArgs(strings.Split(c.command, " "))
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("failed to execute %q: %s", c.command, err)
+			continue
+		}
+
+		reader := csv.NewReader(buf)
+		reader.Comment = '#'
+		lines, err := reader.ReadAll()
+		if err != nil {
+			t.Fatalf("failed to read generated csv: %s", err)
+		}
+		rows := map[string][]string{}
+		for _, line := range lines[1:] {
+			rows[line[0]] = line[1:]
+		}
+		t.Run(c.command, func(t *testing.T) {
+			c.check(t, rows, len(lines[0])-1)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/mpppk/grouping/cmd/option"
+	"github.com/mpppk/grouping/domain"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+func newNextCmd(fs afero.Fs) (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:   "next",
+		Short: "generate groups of next round",
+		Long: `generate groups of next round from existing group file.
+Group file which the new round column is appended to is written to stdout,
+and number of duplicated member pairs before and after the new round is written to stderr.
+Columns of partial group file are rounds counted from the first round of the group file,
+so members can be pinned only by the column of the new round.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			conf, err := option.NewNextCmdConfigFromViper(args)
+			if err != nil {
+				return err
+			}
+
+			members, history, err := domain.ParseGroupFileWithMembers(conf.File)
+			if err != nil {
+				return fmt.Errorf("failed to parse group file from %s: %w", conf.File, err)
+			}
+			if conf.Members != "" {
+				if err := copyAttributes(members, conf.Members); err != nil {
+					return err
+				}
+			}
+
+			availability, err := parseAvailability(conf.Availability)
+			if err != nil {
+				return err
+			}
+			objective, err := newObjective(&conf.ObjectiveConfig)
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := newSolverContext(cmd.Context(), &conf.SolverConfig)
+			defer cancel()
+			next, err := domain.GenerateNextGroups(ctx, members, history, availability, newGroupSizes(&conf.GroupSizeConfig), conf.Trials, objective, newSolver(&conf.SolverConfig), newRand(&conf.SolverConfig))
+			if err != nil {
+				return fmt.Errorf("failed to generate next groups: %w", err)
+			}
+			groupsList := append(history, next)
+
+			before, err := domain.CountDupMemberPairs(history)
+			if err != nil {
+				return fmt.Errorf("failed to count dup member pairs: %w", err)
+			}
+			after, err := domain.CountDupMemberPairs(groupsList)
+			if err != nil {
+				return fmt.Errorf("failed to count dup member pairs: %w", err)
+			}
+
+			comments, err := domain.ParseGroupFileComments(conf.File)
+			if err != nil {
+				return fmt.Errorf("failed to parse comments of group file from %s: %w", conf.File, err)
+			}
+			// headers of rounds held so far such as dates are kept, and only the new round is named
+			headers, err := domain.ParseGroupFileHeaders(conf.File)
+			if err != nil {
+				return fmt.Errorf("failed to parse headers of group file from %s: %w", conf.File, err)
+			}
+			comments = append(comments, fmt.Sprintf("round %d: %s", len(groupsList), reproductionComment(cmd, &conf.SolverConfig)))
+			if err := domain.WriteGroupFile(cmd.OutOrStdout(), comments, headers, members, groupsList); err != nil {
+				return fmt.Errorf("failed to write group file: %w", err)
+			}
+			if _, err := fmt.Fprintf(cmd.ErrOrStderr(), "duplicated member pairs: before %d, after %d\n", before, after); err != nil {
+				return fmt.Errorf("failed to write report: %w", err)
+			}
+			return nil
+		},
+	}
+
+	registerNextCommandFlags := func(cmd *cobra.Command) error {
+		flags := []option.Flag{
+			&option.StringFlag{
+				BaseFlag: &option.BaseFlag{
+					Name:      "file",
+					Usage:     "group file which has rounds held so far",
+					ViperName: "next.file",
+				},
+				IsFileName: true,
+			},
+			&option.StringFlag{
+				BaseFlag: &option.BaseFlag{
+					Name:      "members",
+					Usage:     "member csv file which has attributes and roles of members in the group file",
+					ViperName: "next.members",
+				},
+				IsFileName: true,
+			},
+			&option.StringFlag{
+				BaseFlag: &option.BaseFlag{
+					Name:      "availability",
+					Usage:     "availability csv file which has NAME column and a column for each round. absent, no, x, 0 or empty cell means that the member is absent from the round",
+					ViperName: "next.availability",
+				},
+				IsFileName: true,
+			},
+			&option.IntFlag{
+				BaseFlag: &option.BaseFlag{
+					Name:      "trials",
+					Usage:     "number of attempts to generate groups",
+					ViperName: "next.trials",
+				},
+				Value: 100,
+			},
+		}
+		flags = append(flags, newGroupSizeFlags("next")...)
+		flags = append(flags, newObjectiveFlags("next")...)
+		flags = append(flags, newSolverFlags("next")...)
+		return option.RegisterFlags(cmd, flags)
+	}
+
+	if err := registerNextCommandFlags(cmd); err != nil {
+		return nil, err
+	}
+
+	return cmd, nil
+}
+
+func init() {
+	cmdGenerators = append(cmdGenerators, newNextCmd)
+}
@@ -0,0 +1,67 @@
+package cmd_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/mpppk/grouping/cmd"
+	"github.com/spf13/afero"
+)
+
+func TestNext(t *testing.T) {
+	cases := []struct {
+		command     string
+		wantHeaders string
+		wantErr     string
+	}{
+		{
+			command:     "next --file ../testdata/no_dup_groups.csv --group-size 2",
+			wantHeaders: "NAME,1st,2nd,3rd\n",
+			wantErr:     "duplicated member pairs: before 0, after 0\n",
+		},
+		{
+			command:     "next --file ../testdata/no_dup_groups.csv --group-size 2 --window 2",
+			wantHeaders: "NAME,1st,2nd,3rd\n",
+			wantErr:     "duplicated member pairs: before 0, after 0\n",
+		},
+		{
+			command:     "next --file ../testdata/dated_groups.csv --group-size 2",
+			wantHeaders: "NAME,2020-04-01,2020-04-08,3rd\n",
+			wantErr:     "duplicated member pairs: before 0, after 0\n",
+		},
+		{
+			command:     "next --file ../testdata/dup_groups.csv --group-size 4",
+			wantHeaders: "NAME,1st,2nd,3rd\n",
+			wantErr:     "duplicated member pairs: before 2, after 4\n",
+		},
+	}
+
+	for _, c := range cases {
+		buf, errBuf := new(bytes.Buffer), new(bytes.Buffer)
+		rootCmd, err := cmd.NewRootCmd(afero.NewMemMapFs())
+		if err != nil {
+			t.Errorf("failed to create rootCmd: %s", err)
+		}
+		rootCmd.SetOut(buf)
+		rootCmd.SetErr(errBuf)
+		cmdArgs := strings.Split(c.command, " ")
+		rootCmd.SetArgs(cmdArgs)
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("failed to execute rootCmd: %s", err)
+		}
+
+		if comment, err := buf.ReadString('\n'); err != nil || !strings.HasPrefix(comment, "# round 3: grouping next ") {
+			t.Errorf("unexpected comment: %q", comment)
+		}
+		if get, err := buf.ReadString('\n'); err != nil || c.wantHeaders != get {
+			t.Errorf("unexpected headers: want:%q, get:%q", c.wantHeaders, get)
+		}
+		if rows := strings.Count(buf.String(), "\n"); rows != 4 {
+			t.Errorf("unexpected row num: want:%d, get:%d", 4, rows)
+		}
+		if get := errBuf.String(); c.wantErr != get {
+			t.Errorf("unexpected stderr: want:%q, get:%q", c.wantErr, get)
+		}
+	}
+}
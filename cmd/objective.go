@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/mpppk/grouping/cmd/option"
+	"github.com/mpppk/grouping/domain"
+)
+
+func newObjectiveFlags(cmdName string) []option.Flag {
+	flags := []option.Flag{
+		&option.StringFlag{
+			BaseFlag: &option.BaseFlag{
+				Name:      "constraints",
+				Usage:     "constraint csv file which has lines such as together,NAME,NAME, apart,NAME,NAME pin,NAME,ROUND,GROUP_ID, min,ROLE,COUNT or max,ROLE,COUNT. pair and role constraints are soft if soft or soft:WEIGHT is appended",
+				ViperName: cmdName + ".constraints",
+			},
+			IsFileName: true,
+		},
+		&option.StringFlag{
+			BaseFlag: &option.BaseFlag{
+				Name:      "partial",
+				Usage:     "partial group csv file whose filled cells pin members to the groups. solvers never move pinned members",
+				ViperName: cmdName + ".partial",
+			},
+			IsFileName: true,
+		},
+		&option.StringFlag{
+			BaseFlag: &option.BaseFlag{
+				Name:      "weights",
+				Usage:     "pair weight csv file which has lines such as NAME,NAME,WEIGHT. each meeting of the pair costs the weight instead of duplication, and negative weight means that the pair should meet",
+				ViperName: cmdName + ".weights",
+			},
+			IsFileName: true,
+		},
+		&option.StringFlag{
+			BaseFlag: &option.BaseFlag{
+				Name:      "preferences",
+				Usage:     "preference csv file which has lines such as NAME,NAME,NAME... the first member wants to meet the others in order of preference",
+				ViperName: cmdName + ".preferences",
+			},
+			IsFileName: true,
+		},
+		&option.StringFlag{
+			BaseFlag: &option.BaseFlag{
+				Name:      "balance",
+				Usage:     "comma separated attribute columns of member file such as department,location whose values are spread evenly across groups",
+				ViperName: cmdName + ".balance",
+			},
+		},
+		&option.StringFlag{
+			BaseFlag: &option.BaseFlag{
+				Name:      "rating",
+				Usage:     "numeric attribute column of member file such as skill whose totals are balanced across groups by minimizing their variance",
+				ViperName: cmdName + ".rating",
+			},
+		},
+		&option.BoolFlag{
+			BaseFlag: &option.BaseFlag{
+				Name:      "rating-average",
+				Usage:     "balance average ratings of groups instead of totals, which suits groups of different sizes",
+				ViperName: cmdName + ".ratingAverage",
+			},
+		},
+	}
+	flags = append(flags, newDecayFlags(cmdName)...)
+	return append(flags, newObjectiveWeightFlags(cmdName)...)
+}
+
+func newObjectiveWeightFlags(cmdName string) []option.Flag {
+	return []option.Flag{
+		&option.Float64Flag{
+			BaseFlag: &option.BaseFlag{
+				Name:      "dup-weight",
+				Usage:     "weight of duplicated member pairs in the objective",
+				ViperName: cmdName + ".dupWeight",
+			},
+			Value: 1,
+		},
+		&option.Float64Flag{
+			BaseFlag: &option.BaseFlag{
+				Name:      "balance-weight",
+				Usage:     "weight of attribute imbalance in the objective",
+				ViperName: cmdName + ".balanceWeight",
+			},
+			Value: 1,
+		},
+		&option.Float64Flag{
+			BaseFlag: &option.BaseFlag{
+				Name:      "soft-weight",
+				Usage:     "weight of violations of soft constraints in the objective, which multiplies weight of each soft constraint",
+				ViperName: cmdName + ".softWeight",
+			},
+			Value: 1,
+		},
+		&option.Float64Flag{
+			BaseFlag: &option.BaseFlag{
+				Name:      "rating-weight",
+				Usage:     "weight of variance of group ratings in the objective",
+				ViperName: cmdName + ".ratingWeight",
+			},
+			Value: 1,
+		},
+		&option.Float64Flag{
+			BaseFlag: &option.BaseFlag{
+				Name:      "preference-weight",
+				Usage:     "weight of satisfied preferences in the objective",
+				ViperName: cmdName + ".preferenceWeight",
+			},
+			Value: 1,
+		},
+	}
+}
+
+// newObjective returns objective from conf. Files in conf are parsed.
+// Members in the partial group file are pinned in addition to constraints.
+func newObjective(conf *option.ObjectiveConfig) (*domain.Objective, error) {
+	objective := &domain.Objective{
+		Decay:              newDecay(&conf.DecayConfig),
+		Constraints:        &domain.Constraints{},
+		BalancedAttributes: conf.BalancedAttributes(),
+		Rating:             conf.Rating,
+		RatingAverage:      conf.RatingAverage,
+		Weights: &domain.ObjectiveWeights{
+			Dup:        conf.DupWeight,
+			Balance:    conf.BalanceWeight,
+			Soft:       conf.SoftWeight,
+			Rating:     conf.RatingWeight,
+			Preference: conf.PreferenceWeight,
+		},
+	}
+	if conf.Constraints != "" {
+		constraints, err := domain.ParseConstraintFile(conf.Constraints)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse constraint file from %s: %w", conf.Constraints, err)
+		}
+		objective.Constraints.Add(constraints)
+	}
+	if conf.Partial != "" {
+		groupsList, err := domain.ParseGroupFile(conf.Partial)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse partial group file from %s: %w", conf.Partial, err)
+		}
+		pins, err := domain.NewPins(groupsList)
+		if err != nil {
+			return nil, fmt.Errorf("invalid partial group file %s: %w", conf.Partial, err)
+		}
+		objective.Constraints.Pins = append(objective.Constraints.Pins, pins...)
+	}
+	weights, err := parsePairWeights(conf.Weights)
+	if err != nil {
+		return nil, err
+	}
+	objective.PairWeights = weights
+	preferences, err := parsePreferences(conf.Preferences)
+	if err != nil {
+		return nil, err
+	}
+	objective.Preferences = preferences
+	return objective, nil
+}
+
+// parsePreferences returns preferences in the file, or nil if filePath is empty
+func parsePreferences(filePath string) ([]*domain.Preference, error) {
+	if filePath == "" {
+		return nil, nil
+	}
+	preferences, err := domain.ParsePreferenceFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse preference file from %s: %w", filePath, err)
+	}
+	return preferences, nil
+}
+
+// parsePairWeights returns pair weights in the file, or nil if filePath is empty
+func parsePairWeights(filePath string) ([]*domain.PairWeight, error) {
+	if filePath == "" {
+		return nil, nil
+	}
+	weights, err := domain.ParsePairWeightFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse pair weight file from %s: %w", filePath, err)
+	}
+	return weights, nil
+}
+
+// copyAttributes sets attributes of members in member file to members
+func copyAttributes(members []*domain.Member, memberFile string) error {
+	roster, err := domain.ParseMemberFile(memberFile)
+	if err != nil {
+		return fmt.Errorf("failed to parse member file from %s: %w", memberFile, err)
+	}
+	if err := domain.CopyAttributes(members, roster); err != nil {
+		return fmt.Errorf("failed to load attributes from %s: %w", memberFile, err)
+	}
+	return nil
+}
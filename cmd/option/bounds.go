@@ -0,0 +1,87 @@
+package option
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// BoundsCmdConfig is config for bounds command
+type BoundsCmdConfig struct {
+	MemberNum  int
+	GroupSize  int
+	GroupSizes []int
+	Rounds     int
+}
+
+// NewBoundsCmdConfigFromViper generate config for bounds command from viper
+func NewBoundsCmdConfigFromViper(args []string) (*BoundsCmdConfig, error) {
+	var conf struct {
+		Bounds struct {
+			MemberNum  int
+			GroupSize  int
+			GroupSizes string
+			Rounds     int
+		}
+	}
+	if err := viper.Unmarshal(&conf); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config from viper: %w", err)
+	}
+
+	groupSizes, err := parseIntList(conf.Bounds.GroupSizes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse group-sizes: %w", err)
+	}
+
+	c := &BoundsCmdConfig{
+		MemberNum:  conf.Bounds.MemberNum,
+		GroupSize:  conf.Bounds.GroupSize,
+		GroupSizes: groupSizes,
+		Rounds:     conf.Bounds.Rounds,
+	}
+	if err := c.validate(); err != nil {
+		return nil, fmt.Errorf("failed to create bounds cmd config: %w", err)
+	}
+	return c, nil
+}
+
+func (c *BoundsCmdConfig) validate() error {
+	if c.Rounds < 0 {
+		return fmt.Errorf("rounds must not be negative: %d", c.Rounds)
+	}
+	if len(c.GroupSizes) > 0 {
+		total := 0
+		for _, size := range c.GroupSizes {
+			total += size
+		}
+		if c.MemberNum != 0 && c.MemberNum != total {
+			return fmt.Errorf("sum of group-sizes(%d) is not equal to member-num(%d)", total, c.MemberNum)
+		}
+		return nil
+	}
+	if c.MemberNum < 2 {
+		return fmt.Errorf("member-num must be greater than 1: %d", c.MemberNum)
+	}
+	if c.GroupSize < 2 {
+		return fmt.Errorf("group-size must be greater than 1: %d", c.GroupSize)
+	}
+	return nil
+}
+
+// parseIntList parses comma separated integers such as "4,4,3"
+func parseIntList(s string) ([]int, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var list []int
+	for _, v := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert %s to int: %w", v, err)
+		}
+		list = append(list, n)
+	}
+	return list, nil
+}
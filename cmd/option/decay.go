@@ -0,0 +1,26 @@
+package option
+
+import "fmt"
+
+// DecayConfig is config for decay of penalty of repeated meetings. Decay is disabled if both fields are 0.
+type DecayConfig struct {
+	// HalfLife is number of rounds in which penalty halves
+	HalfLife float64
+	// Window is number of rounds after which meeting again is not penalized
+	Window int
+}
+
+// Enabled returns true if penalty decays
+func (c *DecayConfig) Enabled() bool {
+	return c.HalfLife != 0 || c.Window != 0
+}
+
+func (c *DecayConfig) validate() error {
+	if c.HalfLife < 0 || c.Window < 0 {
+		return fmt.Errorf("half-life and window must not be negative: %g, %d", c.HalfLife, c.Window)
+	}
+	if c.HalfLife > 0 && c.Window > 0 {
+		return fmt.Errorf("half-life and window can not be used together")
+	}
+	return nil
+}
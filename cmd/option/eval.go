@@ -8,7 +8,19 @@ import (
 
 // EvalCmdConfig is config for eval command
 type EvalCmdConfig struct {
-	File string
+	File      string
+	Details   bool
+	ByMember  bool
+	Coverage  bool
+	Bound     bool
+	Optimal   bool
+	MaxNodes  int
+	Members   string
+	Breakdown bool
+	// MinGroupSize and MaxGroupSize are bounds of group sizes which are checked if they are positive
+	MinGroupSize    int
+	MaxGroupSize    int
+	ObjectiveConfig `mapstructure:",squash"`
 }
 
 // NewEvalCmdConfigFromViper generate config for eval command from viper
@@ -26,5 +38,14 @@ func NewEvalCmdConfigFromViper(args []string) (*EvalCmdConfig, error) {
 }
 
 func (c *EvalCmdConfig) validate() error {
+	if c.MinGroupSize < 0 || c.MaxGroupSize < 0 {
+		return fmt.Errorf("min-group-size and max-group-size must not be negative: %d, %d", c.MinGroupSize, c.MaxGroupSize)
+	}
+	if err := c.ObjectiveConfig.validate(); err != nil {
+		return err
+	}
+	if c.Optimal && c.MaxNodes < 1 {
+		return fmt.Errorf("max-nodes must be positive: %d", c.MaxNodes)
+	}
 	return nil
 }
@@ -1,10 +1,18 @@
 package option
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
 
+// viperNameAnnotation is annotation key of flags which has name of the flag in viper
+const viperNameAnnotation = "viper-name"
+
 // StringFlag represents flag which can be specified as string
 type StringFlag struct {
 	*BaseFlag
@@ -149,7 +157,36 @@ func RegisterFlag(cmd *cobra.Command, flag Flag) error {
 	if err := viper.BindPFlag(baseFlag.getViperName(), flagSet.Lookup(baseFlag.Name)); err != nil {
 		return err
 	}
-	return nil
+	return flagSet.SetAnnotation(baseFlag.Name, viperNameAnnotation, []string{baseFlag.getViperName()})
+}
+
+// FormatFlags formats local flags of cmd with their values in viper, such as "--rounds 5 --seed 1".
+// Values of overrides are used instead of values in viper. Flags which have empty value are omitted.
+func FormatFlags(cmd *cobra.Command, overrides map[string]string) string {
+	var args []string
+	cmd.LocalFlags().VisitAll(func(flag *pflag.Flag) {
+		viperNames, ok := flag.Annotations[viperNameAnnotation]
+		if !ok {
+			return
+		}
+		value, ok := overrides[flag.Name]
+		if !ok {
+			value = fmt.Sprint(viper.Get(viperNames[0]))
+		}
+		if value == "" {
+			return
+		}
+		if strings.ContainsAny(value, " \t\"'") {
+			value = strconv.Quote(value)
+		}
+		// flags such as bool flags take a value only in the form of --name=value
+		if flag.NoOptDefVal != "" {
+			args = append(args, "--"+flag.Name+"="+value)
+			return
+		}
+		args = append(args, "--"+flag.Name, value)
+	})
+	return strings.Join(args, " ")
 }
 
 // RegisterStringFlag register string flag to provided cmd and viper
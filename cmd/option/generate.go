@@ -0,0 +1,57 @@
+package option
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/viper"
+)
+
+// GenerateCmdConfig is config for generate command
+type GenerateCmdConfig struct {
+	Members         string
+	Rounds          int
+	Trials          int
+	Availability    string
+	GroupSizeConfig `mapstructure:",squash"`
+	ObjectiveConfig `mapstructure:",squash"`
+	SolverConfig    `mapstructure:",squash"`
+}
+
+// NewGenerateCmdConfigFromViper generate config for generate command from viper
+func NewGenerateCmdConfigFromViper(args []string) (*GenerateCmdConfig, error) {
+	// flags of generate command are bound to viper under "generate" key
+	// so that they do not conflict with flags of other commands which have same name.
+	var conf struct{ Generate GenerateCmdConfig }
+	if err := viper.Unmarshal(&conf); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config from viper: %w", err)
+	}
+
+	if err := conf.Generate.validate(); err != nil {
+		return nil, fmt.Errorf("failed to create generate cmd config: %w", err)
+	}
+
+	return &conf.Generate, nil
+}
+
+func (c *GenerateCmdConfig) validate() error {
+	if c.Members == "" {
+		return errors.New("members file must be specified")
+	}
+	if c.Rounds < 1 {
+		return fmt.Errorf("rounds must be positive: %d", c.Rounds)
+	}
+	if c.Trials < 1 {
+		return fmt.Errorf("trials must be positive: %d", c.Trials)
+	}
+	if c.Strategy == StrategyExact && (c.Constraints != "" || c.Partial != "" || c.Weights != "" || c.Balance != "" || c.Preferences != "" || c.Rating != "" || c.Availability != "" || c.DecayConfig.Enabled()) {
+		return fmt.Errorf("%s strategy can not be used with constraints, partial, weights, balance, preferences, rating, availability or decay", StrategyExact)
+	}
+	if err := c.ObjectiveConfig.validate(); err != nil {
+		return err
+	}
+	if err := c.GroupSizeConfig.validate(); err != nil {
+		return err
+	}
+	return c.SolverConfig.validate()
+}
@@ -0,0 +1,56 @@
+package option
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/viper"
+)
+
+// NextCmdConfig is config for next command
+type NextCmdConfig struct {
+	File   string
+	Trials int
+	// Members is member file which has attributes of members in the group file
+	Members         string
+	Availability    string
+	GroupSizeConfig `mapstructure:",squash"`
+	ObjectiveConfig `mapstructure:",squash"`
+	SolverConfig    `mapstructure:",squash"`
+}
+
+// NewNextCmdConfigFromViper generate config for next command from viper
+func NewNextCmdConfigFromViper(args []string) (*NextCmdConfig, error) {
+	var conf struct{ Next NextCmdConfig }
+	if err := viper.Unmarshal(&conf); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config from viper: %w", err)
+	}
+
+	if err := conf.Next.validate(); err != nil {
+		return nil, fmt.Errorf("failed to create next cmd config: %w", err)
+	}
+
+	return &conf.Next, nil
+}
+
+func (c *NextCmdConfig) validate() error {
+	if c.File == "" {
+		return errors.New("group file must be specified")
+	}
+	if c.Trials < 1 {
+		return fmt.Errorf("trials must be positive: %d", c.Trials)
+	}
+	if c.Balance != "" && c.Members == "" {
+		return errors.New("members file must be specified to balance attributes")
+	}
+	if c.Strategy == StrategyExact {
+		return fmt.Errorf("%s strategy can not be used for next command", StrategyExact)
+	}
+	if err := c.ObjectiveConfig.validate(); err != nil {
+		return err
+	}
+	if err := c.GroupSizeConfig.validate(); err != nil {
+		return err
+	}
+	return c.SolverConfig.validate()
+}
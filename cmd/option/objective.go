@@ -0,0 +1,42 @@
+package option
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ObjectiveConfig is config for what generators minimize, which is shared by commands generating groups
+type ObjectiveConfig struct {
+	Constraints string
+	Partial     string
+	Weights     string
+	Balance     string
+	Preferences string
+	// Rating is a numeric attribute column whose totals are balanced, or averages if RatingAverage is true
+	Rating        string
+	RatingAverage bool
+	DecayConfig   `mapstructure:",squash"`
+	// DupWeight, BalanceWeight, SoftWeight, RatingWeight and PreferenceWeight are weights of terms of the objective
+	DupWeight        float64
+	BalanceWeight    float64
+	SoftWeight       float64
+	RatingWeight     float64
+	PreferenceWeight float64
+}
+
+func (c *ObjectiveConfig) validate() error {
+	if c.DupWeight < 0 || c.BalanceWeight < 0 || c.SoftWeight < 0 || c.RatingWeight < 0 || c.PreferenceWeight < 0 {
+		return fmt.Errorf("dup-weight, balance-weight, soft-weight, rating-weight and preference-weight must not be negative: %g, %g, %g, %g, %g", c.DupWeight, c.BalanceWeight, c.SoftWeight, c.RatingWeight, c.PreferenceWeight)
+	}
+	return c.DecayConfig.validate()
+}
+
+// BalancedAttributes returns names of attributes in comma separated Balance
+func (c *ObjectiveConfig) BalancedAttributes() (names []string) {
+	for _, name := range strings.Split(c.Balance, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return
+}
@@ -0,0 +1,58 @@
+package option
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GroupSizeConfig is config for sizes of groups, which is shared by commands generating groups
+type GroupSizeConfig struct {
+	GroupSize    int
+	MinGroupSize int
+	MaxGroupSize int
+	// GroupSizes is comma separated sizes of groups such as "4,4,3". Sizes of each round are separated by "/".
+	GroupSizes string
+}
+
+// GroupSizesList returns sizes of groups of each round in GroupSizes
+func (c *GroupSizeConfig) GroupSizesList() ([][]int, error) {
+	if c.GroupSizes == "" {
+		return nil, nil
+	}
+	var list [][]int
+	for _, s := range strings.Split(c.GroupSizes, "/") {
+		sizes, err := parseIntList(s)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, sizes)
+	}
+	return list, nil
+}
+
+func (c *GroupSizeConfig) validate() error {
+	list, err := c.GroupSizesList()
+	if err != nil {
+		return fmt.Errorf("failed to parse group-sizes: %w", err)
+	}
+	for _, sizes := range list {
+		if len(sizes) == 0 {
+			return fmt.Errorf("group-sizes of a round is empty: %s", c.GroupSizes)
+		}
+		for _, size := range sizes {
+			if size < 1 {
+				return fmt.Errorf("group-sizes must be positive: %s", c.GroupSizes)
+			}
+		}
+	}
+	if c.GroupSize < 2 {
+		return fmt.Errorf("group-size must be greater than 1: %d", c.GroupSize)
+	}
+	if c.MinGroupSize < 0 || c.MaxGroupSize < 0 {
+		return fmt.Errorf("min-group-size and max-group-size must not be negative: %d, %d", c.MinGroupSize, c.MaxGroupSize)
+	}
+	if c.MaxGroupSize > 0 && c.MinGroupSize > c.MaxGroupSize {
+		return fmt.Errorf("min-group-size(%d) is greater than max-group-size(%d)", c.MinGroupSize, c.MaxGroupSize)
+	}
+	return nil
+}
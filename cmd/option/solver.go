@@ -0,0 +1,75 @@
+package option
+
+import (
+	"fmt"
+	"time"
+)
+
+// Strategies which can be specified by strategy flag
+const (
+	StrategyGreedy    = "greedy"
+	StrategyAnnealing = "annealing"
+	StrategyExact     = "exact"
+	StrategyTabu      = "tabu"
+	StrategyGenetic   = "genetic"
+)
+
+// SolverConfig is config for solvers which is shared by commands generating groups
+type SolverConfig struct {
+	Strategy    string
+	Iterations  int
+	Temperature float64
+	Seed        int64
+	MaxNodes    int
+	Starts      int
+	Workers     int
+	TimeLimit   time.Duration
+	// TabuIterations and TabuTenure are used by tabu strategy
+	TabuIterations int
+	TabuTenure     int
+	// Population and Generations are used by genetic strategy
+	Population  int
+	Generations int
+}
+
+func (c *SolverConfig) validate() error {
+	switch c.Strategy {
+	case StrategyGreedy, StrategyAnnealing, StrategyExact, StrategyTabu, StrategyGenetic:
+	default:
+		return fmt.Errorf("unknown strategy: %s", c.Strategy)
+	}
+	if c.Iterations < 0 {
+		return fmt.Errorf("iterations must not be negative: %d", c.Iterations)
+	}
+	if c.TabuIterations < 0 {
+		return fmt.Errorf("tabu-iterations must not be negative: %d", c.TabuIterations)
+	}
+	if c.TabuTenure < 0 {
+		return fmt.Errorf("tabu-tenure must not be negative: %d", c.TabuTenure)
+	}
+	if c.Population < 2 {
+		return fmt.Errorf("population must be greater than 1: %d", c.Population)
+	}
+	if c.Generations < 0 {
+		return fmt.Errorf("generations must not be negative: %d", c.Generations)
+	}
+	if c.Temperature <= 0 {
+		return fmt.Errorf("temperature must be positive: %v", c.Temperature)
+	}
+	if c.Starts < 0 {
+		return fmt.Errorf("starts must not be negative: %d", c.Starts)
+	}
+	if c.Workers < 0 {
+		return fmt.Errorf("workers must not be negative: %d", c.Workers)
+	}
+	if c.Seed < 0 {
+		return fmt.Errorf("seed must not be negative: %d", c.Seed)
+	}
+	if c.TimeLimit < 0 {
+		return fmt.Errorf("time-limit must not be negative: %s", c.TimeLimit)
+	}
+	if c.MaxNodes < 1 {
+		return fmt.Errorf("max-nodes must be positive: %d", c.MaxNodes)
+	}
+	return nil
+}
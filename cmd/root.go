@@ -1,8 +1,10 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
 
 	"github.com/mpppk/grouping/util"
 
@@ -86,12 +88,26 @@ func Execute() {
 	if err != nil {
 		panic(err)
 	}
-	if err := rootCmd.Execute(); err != nil {
+	if err := rootCmd.ExecuteContext(newInterruptContext()); err != nil {
 		fmt.Print(util.PrettyPrintError(err))
 		os.Exit(1)
 	}
 }
 
+// newInterruptContext returns context which is canceled by Ctrl-C,
+// so that long running commands can stop and output the best result found so far
+func newInterruptContext() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt)
+	go func() {
+		<-c
+		signal.Stop(c)
+		cancel()
+	}()
+	return ctx
+}
+
 func init() {
 	cobra.OnInitialize(initConfig)
 }
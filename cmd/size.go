@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"github.com/mpppk/grouping/cmd/option"
+	"github.com/mpppk/grouping/domain"
+)
+
+func newGroupSizeFlags(cmdName string) []option.Flag {
+	return []option.Flag{
+		&option.IntFlag{
+			BaseFlag: &option.BaseFlag{
+				Name:      "group-size",
+				Usage:     "preferred number of members in each group",
+				ViperName: cmdName + ".groupSize",
+			},
+			Value: 4,
+		},
+		&option.IntFlag{
+			BaseFlag: &option.BaseFlag{
+				Name:      "min-group-size",
+				Usage:     "minimum number of members in each group (no limit if 0)",
+				ViperName: cmdName + ".minGroupSize",
+			},
+		},
+		&option.IntFlag{
+			BaseFlag: &option.BaseFlag{
+				Name:      "max-group-size",
+				Usage:     "maximum number of members in each group (no limit if 0)",
+				ViperName: cmdName + ".maxGroupSize",
+			},
+		},
+		&option.StringFlag{
+			BaseFlag: &option.BaseFlag{
+				Name:      "group-sizes",
+				Usage:     `exact sizes of groups such as 4,4,3. sizes of each round can be separated by "/" such as 4,4,3/4,3,3,1 and the last sizes are used for the rest of rounds`,
+				ViperName: cmdName + ".groupSizes",
+			},
+		},
+	}
+}
+
+// newGroupSizes returns GroupSizes from conf which has been validated
+func newGroupSizes(conf *option.GroupSizeConfig) *domain.GroupSizes {
+	exact, _ := conf.GroupSizesList()
+	return &domain.GroupSizes{
+		Size:  conf.GroupSize,
+		Min:   conf.MinGroupSize,
+		Max:   conf.MaxGroupSize,
+		Exact: exact,
+	}
+}
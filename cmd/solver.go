@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"math/rand"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/mpppk/grouping/cmd/option"
+	"github.com/mpppk/grouping/domain"
+	"github.com/spf13/cobra"
+)
+
+func newSolverFlags(cmdName string) []option.Flag {
+	return []option.Flag{
+		&option.StringFlag{
+			BaseFlag: &option.BaseFlag{
+				Name: "strategy",
+				Usage: fmt.Sprintf("strategy to improve groups (%s|%s|%s|%s|%s)",
+					option.StrategyGreedy, option.StrategyAnnealing, option.StrategyTabu, option.StrategyGenetic, option.StrategyExact),
+				ViperName: cmdName + ".strategy",
+			},
+			Value: option.StrategyAnnealing,
+		},
+		&option.IntFlag{
+			BaseFlag: &option.BaseFlag{
+				Name:      "iterations",
+				Usage:     "number of iterations of annealing. exact strategy also uses annealing to find the initial upper bound",
+				ViperName: cmdName + ".iterations",
+			},
+			Value: 200000,
+		},
+		&option.Float64Flag{
+			BaseFlag: &option.BaseFlag{
+				Name:      "temperature",
+				Usage:     "initial temperature of annealing",
+				ViperName: cmdName + ".temperature",
+			},
+			Value: 2,
+		},
+		&option.IntFlag{
+			BaseFlag: &option.BaseFlag{
+				Name:      "tabu-iterations",
+				Usage:     "number of iterations of tabu search. each iteration evaluates all swaps of members",
+				ViperName: cmdName + ".tabuIterations",
+			},
+			Value: 2000,
+		},
+		&option.IntFlag{
+			BaseFlag: &option.BaseFlag{
+				Name:      "tabu-tenure",
+				Usage:     "number of iterations in which swapped members can not be moved by tabu search (decided from number of members if 0)",
+				ViperName: cmdName + ".tabuTenure",
+			},
+		},
+		&option.IntFlag{
+			BaseFlag: &option.BaseFlag{
+				Name:      "population",
+				Usage:     "number of schedules in each generation of genetic algorithm",
+				ViperName: cmdName + ".population",
+			},
+			Value: 50,
+		},
+		&option.IntFlag{
+			BaseFlag: &option.BaseFlag{
+				Name:      "generations",
+				Usage:     "number of generations of genetic algorithm",
+				ViperName: cmdName + ".generations",
+			},
+			Value: 1000,
+		},
+		&option.IntFlag{
+			BaseFlag: &option.BaseFlag{
+				Name:      "max-nodes",
+				Usage:     "maximum number of nodes which exact strategy explores",
+				ViperName: cmdName + ".maxNodes",
+			},
+			Value: 50000000,
+		},
+		&option.IntFlag{
+			BaseFlag: &option.BaseFlag{
+				Name:      "starts",
+				Usage:     "number of independent searches of annealing, tabu search or genetic algorithm (same as workers if 0, or unlimited until time-limit if time-limit is specified)",
+				ViperName: cmdName + ".starts",
+			},
+		},
+		&option.IntFlag{
+			BaseFlag: &option.BaseFlag{
+				Name:      "workers",
+				Usage:     "number of searches which run concurrently (GOMAXPROCS if 0)",
+				ViperName: cmdName + ".workers",
+			},
+		},
+		&option.StringFlag{
+			BaseFlag: &option.BaseFlag{
+				Name:      "time-limit",
+				Usage:     "keep improving groups until the duration such as 30s passes",
+				ViperName: cmdName + ".timeLimit",
+			},
+			Value: "0s",
+		},
+		&option.Int64Flag{
+			BaseFlag: &option.BaseFlag{
+				Name:      "seed",
+				Usage:     "random seed (random seed is used if 0). the seed is recorded in the output",
+				ViperName: cmdName + ".seed",
+			},
+		},
+	}
+}
+
+func newSolver(conf *option.SolverConfig) domain.Solver {
+	switch conf.Strategy {
+	case option.StrategyAnnealing, option.StrategyExact:
+		solver := domain.NewAnnealingSolver(conf.Iterations)
+		solver.InitialTemperature = conf.Temperature
+		return newMultiStartSolver(solver, conf)
+	case option.StrategyTabu:
+		solver := domain.NewTabuSolver(conf.TabuIterations)
+		solver.Tenure = conf.TabuTenure
+		return newMultiStartSolver(solver, conf)
+	case option.StrategyGenetic:
+		return newMultiStartSolver(domain.NewGeneticSolver(conf.Population, conf.Generations), conf)
+	default:
+		return &domain.GreedySolver{}
+	}
+}
+
+// newMultiStartSolver runs solver from multiple starts and logs the progress
+func newMultiStartSolver(solver domain.Solver, conf *option.SolverConfig) *domain.MultiStartSolver {
+	starts, workers := resolveParallelism(conf)
+	multiStartSolver := domain.NewMultiStartSolver(solver, starts, workers)
+
+	// progress is shown by default only if it takes a while
+	level := "debug"
+	if conf.TimeLimit > 0 {
+		level = "info"
+	}
+	multiStartSolver.OnProgress = func(p *domain.Progress) {
+		log.Printf("%s: elapsed: %s, best cost: %g, finished starts: %d, iterations/sec: %.0f",
+			level, p.Elapsed.Round(time.Millisecond), p.BestCost, p.Starts, p.IterationsPerSecond())
+	}
+	multiStartSolver.OnStartDone = func(start int, cost float64) {
+		log.Printf("debug: start %d finished with cost %g", start, cost)
+	}
+	return multiStartSolver
+}
+
+// newSolverContext returns context which is done when time limit passes
+func newSolverContext(parent context.Context, conf *option.SolverConfig) (context.Context, context.CancelFunc) {
+	if conf.TimeLimit > 0 {
+		return context.WithTimeout(parent, conf.TimeLimit)
+	}
+	return context.WithCancel(parent)
+}
+
+// newRand returns random generator from the seed of conf.
+// If the seed is not specified, a random seed is chosen and set to conf so that it can be recorded.
+func newRand(conf *option.SolverConfig) *rand.Rand {
+	if conf.Seed == 0 {
+		conf.Seed = rand.New(rand.NewSource(time.Now().UnixNano())).Int63n(math.MaxInt32) + 1
+	}
+	if conf.TimeLimit > 0 {
+		log.Printf("warn: result depends on how far the search goes within time-limit, so it may not be reproduced by the seed")
+	}
+	return rand.New(rand.NewSource(conf.Seed))
+}
+
+// resolveParallelism returns number of starts and workers of multi-start solvers.
+// Workers is GOMAXPROCS if it is 0, and starts is number of workers if it is 0,
+// or starts are repeated until time limit if time limit is set except for exact strategy.
+func resolveParallelism(conf *option.SolverConfig) (starts, workers int) {
+	workers = conf.Workers
+	if workers == 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	starts = conf.Starts
+	if starts == 0 {
+		starts = workers
+		// exact strategy needs time after the annealing which finds its upper bound
+		if conf.TimeLimit > 0 && conf.Strategy != option.StrategyExact {
+			starts = -1
+		}
+	}
+	return
+}
+
+// reproductionComment returns command line which reproduces the result.
+// Number of starts and workers which depend on GOMAXPROCS are recorded as resolved values, as well as seed.
+func reproductionComment(cmd *cobra.Command, conf *option.SolverConfig) string {
+	overrides := map[string]string{"seed": strconv.FormatInt(conf.Seed, 10)}
+	starts, workers := resolveParallelism(conf)
+	if starts > 0 {
+		overrides["starts"] = strconv.Itoa(starts)
+	}
+	overrides["workers"] = strconv.Itoa(workers)
+	flags := option.FormatFlags(cmd, overrides)
+	return fmt.Sprintf("%s %s", cmd.CommandPath(), flags)
+}
+
+func printExactResult(w io.Writer, result *domain.ExactResult) error {
+	_, err := fmt.Fprintf(w, "duplicated member pairs: %d\nlower bound: %d\noptimal: %t (%s)\n",
+		result.Dup, result.LowerBound, result.Optimal(), result.Proof())
+	return err
+}
@@ -0,0 +1,164 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+)
+
+// attributeCost is a Cost which counts members outside of fair share of attribute values in each group.
+// Fair share of a value in a group is proportion of the value among all members multiplied by size of the group,
+// and a group may have floor or ceil of the fair share without cost.
+// Members who do not have a value of the attribute are not counted.
+type attributeCost struct {
+	// values[i][m] is index of the value of i-th attribute of member m, or -1 if m does not have the value
+	values [][]int
+	// shares[i][v] is proportion of v-th value of i-th attribute among all members
+	shares [][]float64
+}
+
+func newAttributeCost(members []*Member, names []string) (*attributeCost, error) {
+	c := &attributeCost{}
+	for _, name := range names {
+		indexes := map[string]int{}
+		values := make([]int, len(members))
+		var counts []int
+		for m, member := range members {
+			value, ok := member.Attributes[name]
+			if !ok || value == "" {
+				values[m] = -1
+				continue
+			}
+			index, ok := indexes[value]
+			if !ok {
+				index = len(counts)
+				indexes[value] = index
+				counts = append(counts, 0)
+			}
+			values[m] = index
+			counts[index]++
+		}
+		if len(counts) == 0 {
+			return nil, fmt.Errorf("no member has attribute: %s", name)
+		}
+		shares := make([]float64, len(counts))
+		for v, count := range counts {
+			shares[v] = float64(count) / float64(len(members))
+		}
+		c.values = append(c.values, values)
+		c.shares = append(c.shares, shares)
+	}
+	return c, nil
+}
+
+// outside returns number of members of a value outside of fair share in a group of size
+func (c *attributeCost) outside(attr, value, count, size int) int {
+	share := c.shares[attr][value] * float64(size)
+	// small epsilon absorbs rounding error of floating point numbers such as 0.5*4
+	low, high := int(math.Floor(share+1e-9)), int(math.Ceil(share-1e-9))
+	switch {
+	case count < low:
+		return low - count
+	case count > high:
+		return count - high
+	}
+	return 0
+}
+
+// groupImbalance returns imbalance of attr in group
+func (c *attributeCost) groupImbalance(attr int, group []int) (total int) {
+	counts := make([]int, len(c.shares[attr]))
+	for _, m := range group {
+		if v := c.values[attr][m]; v != -1 {
+			counts[v]++
+		}
+	}
+	for v, count := range counts {
+		total += c.outside(attr, v, count, len(group))
+	}
+	return
+}
+
+// roundImbalance returns imbalance of attr in rd
+func (c *attributeCost) roundImbalance(attr int, rd *round) (total int) {
+	for _, group := range rd.groups {
+		total += c.groupImbalance(attr, group)
+	}
+	return
+}
+
+// Cost returns total imbalance of all attributes in all rounds
+func (c *attributeCost) Cost(s *Schedule) float64 {
+	total := 0
+	for attr := range c.values {
+		for _, rd := range s.rounds {
+			total += c.roundImbalance(attr, rd)
+		}
+	}
+	return float64(total)
+}
+
+// roundRange returns twice number of members for each attribute.
+// Members below floor of fair share and members above ceil of it in a group are at most twice size of the group.
+func (c *attributeCost) roundRange(memberNum int) float64 {
+	return float64(2 * memberNum * len(c.values))
+}
+
+// SwapDelta returns difference of imbalance caused by swap.
+// Only counts of values of a and b in their groups change.
+func (c *attributeCost) SwapDelta(s *Schedule, r, a, b int) float64 {
+	rd := s.rounds[r]
+	ga, gb := rd.groupIndex[a], rd.groupIndex[b]
+	if ga == gb {
+		return 0
+	}
+	delta := 0
+	for attr, values := range c.values {
+		va, vb := values[a], values[b]
+		if va == vb {
+			continue
+		}
+		for _, g := range []int{ga, gb} {
+			group := rd.groups[g]
+			countA, countB := 0, 0
+			for _, m := range group {
+				switch values[m] {
+				case -1:
+				case va:
+					countA++
+				case vb:
+					countB++
+				}
+			}
+			// group ga loses a value of a and gets a value of b, and vice versa
+			diff := 1
+			if g == gb {
+				diff = -1
+			}
+			if va != -1 {
+				delta += c.outside(attr, va, countA-diff, len(group)) - c.outside(attr, va, countA, len(group))
+			}
+			if vb != -1 {
+				delta += c.outside(attr, vb, countB+diff, len(group)) - c.outside(attr, vb, countB, len(group))
+			}
+		}
+	}
+	return float64(delta)
+}
+
+// AttributeImbalances returns imbalance of attribute name in each round of groupsList.
+// Imbalance is number of members outside of fair share of values of the attribute in each group, so 0 is the most balanced.
+func AttributeImbalances(members []*Member, groupsList []Groups, name string) ([]int, error) {
+	s, err := NewScheduleFromGroupsList(members, groupsList)
+	if err != nil {
+		return nil, err
+	}
+	c, err := newAttributeCost(members, []string{name})
+	if err != nil {
+		return nil, err
+	}
+	imbalances := make([]int, len(s.rounds))
+	for r, rd := range s.rounds {
+		imbalances[r] = c.roundImbalance(0, rd)
+	}
+	return imbalances, nil
+}
@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func newTestMembersWithAttributes(attributes ...map[string]string) []*Member {
+	var members []*Member
+	for i, a := range attributes {
+		members = append(members, &Member{ID: MemberID(i + 1), Name: fmt.Sprintf("member%d", i), Attributes: a})
+	}
+	return members
+}
+
+func Test_parseMemberLines_Attributes(t *testing.T) {
+	members, err := parseMemberLines([][]string{
+		{"ID", "NAME", "department", "location"},
+		{"1", "alice", "sales", " tokyo"},
+		{"2", "bob", "dev", ""},
+	})
+	if err != nil {
+		t.Fatalf("parseMemberLines() error = %v", err)
+	}
+	want := []map[string]string{
+		{"department": "sales", "location": "tokyo"},
+		{"department": "dev", "location": ""},
+	}
+	for i, member := range members {
+		if !reflect.DeepEqual(member.Attributes, want[i]) {
+			t.Errorf("parseMemberLines() attributes of %s = %v, want %v", member.Name, member.Attributes, want[i])
+		}
+	}
+	if got := AttributeNames(members); !reflect.DeepEqual(got, []string{"department", "location"}) {
+		t.Errorf("AttributeNames() = %v", got)
+	}
+	if got := AttributeNames(members, "location"); !reflect.DeepEqual(got, []string{"department"}) {
+		t.Errorf("AttributeNames() = %v, excluded attribute must not be returned", got)
+	}
+}
+
+func TestAttributeImbalances(t *testing.T) {
+	members := newTestMembersWithAttributes(
+		map[string]string{"team": "a"},
+		map[string]string{"team": "a"},
+		map[string]string{"team": "b"},
+		map[string]string{"team": "b"},
+		map[string]string{"team": ""},
+		map[string]string{"team": "b"},
+	)
+	groupsList, err := parseGroupLines([][]string{
+		{"NAME", "1st", "2nd"},
+		{"member0", "1", "1"},
+		{"member1", "1", "2"},
+		{"member2", "2", "1"},
+		{"member3", "2", "2"},
+		{"member4", "1", "1"},
+		{"member5", "2", "2"},
+	})
+	if err != nil {
+		t.Fatalf("failed to parse group lines: %v", err)
+	}
+	// fair share of a is 1 and fair share of b is 1 or 2 in groups of 3
+	got, err := AttributeImbalances(members, groupsList, "team")
+	if err != nil {
+		t.Fatalf("AttributeImbalances() error = %v", err)
+	}
+	if want := []int{4, 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("AttributeImbalances() = %v, want %v", got, want)
+	}
+	if _, err := AttributeImbalances(members, groupsList, "location"); err == nil {
+		t.Errorf("AttributeImbalances() must fail for unknown attribute")
+	}
+}
+
+func Test_attributeCost_SwapDelta(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	s := newTestSchedule(t, 13, 4, 4, rnd)
+	teams, levels := []string{"a", "b", "c", ""}, []string{"junior", "senior"}
+	for m, member := range s.members {
+		member.Attributes = map[string]string{"team": teams[m%len(teams)], "level": levels[m%3%2]}
+	}
+	cost, err := newAttributeCost(s.members, []string{"team", "level"})
+	if err != nil {
+		t.Fatalf("failed to create cost: %v", err)
+	}
+	assertSwapDelta(t, s, cost, rnd, 200)
+}
+
+func TestGenerateGroups_BalancedAttributes(t *testing.T) {
+	var attributes []map[string]string
+	for i := 0; i < 16; i++ {
+		attributes = append(attributes, map[string]string{"team": fmt.Sprint(i / 4)})
+	}
+	members := newTestMembersWithAttributes(attributes...)
+	rnd := rand.New(rand.NewSource(1))
+	objective := &Objective{BalancedAttributes: []string{"team"}}
+	got, err := GenerateGroups(context.Background(), members, nil, 3, NewGroupSizes(4), 10, objective, NewAnnealingSolver(100000), rnd)
+	if err != nil {
+		t.Fatalf("GenerateGroups() error = %v", err)
+	}
+	imbalances, err := AttributeImbalances(members, got, "team")
+	if err != nil {
+		t.Fatalf("failed to evaluate balance: %v", err)
+	}
+	if want := []int{0, 0, 0}; !reflect.DeepEqual(imbalances, want) {
+		t.Errorf("GenerateGroups() imbalances = %v, want %v", imbalances, want)
+	}
+}
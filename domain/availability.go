@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Availability has rounds which each member is absent from.
+// Members who are not listed and rounds which are not listed are regarded as present.
+// nil Availability means that every member attends every round.
+type Availability struct {
+	// absent[name][r] is true if the member is absent from 0-based round r
+	absent map[string]map[int]bool
+}
+
+// newAvailability returns Availability from names of absent members of each round
+func newAvailability(absentNamesList [][]string) *Availability {
+	a := &Availability{absent: map[string]map[int]bool{}}
+	for r, names := range absentNamesList {
+		for _, name := range names {
+			a.setAbsent(name, r)
+		}
+	}
+	return a
+}
+
+func (a *Availability) setAbsent(name string, r int) {
+	if _, ok := a.absent[name]; !ok {
+		a.absent[name] = map[int]bool{}
+	}
+	a.absent[name][r] = true
+}
+
+// ParseAvailabilityFile parses availability csv file which has NAME column and a column for each round.
+// Cells such as "absent", "no", "x" or "0", and empty cells mean that the member is absent from the round.
+// Cells such as "present", "yes", "o" or "1" mean that the member attends the round.
+func ParseAvailabilityFile(filePath string) (*Availability, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file from %s", filePath)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.Comment = commentPrefix
+	lines, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse csv from %s: %w", filePath, err)
+	}
+	return parseAvailabilityLines(lines)
+}
+
+func parseAvailabilityLines(lines [][]string) (*Availability, error) {
+	if err := validateMemberFile(lines); err != nil {
+		return nil, fmt.Errorf("failed to parse availability file: %w", err)
+	}
+	nameIndex, ok := findNameIndex(lines[0])
+	if !ok {
+		return nil, fmt.Errorf("failed to find NAME column")
+	}
+
+	a := &Availability{absent: map[string]map[int]bool{}}
+	for _, line := range lines[1:] {
+		r := 0
+		for i, cell := range line {
+			if i == nameIndex {
+				continue
+			}
+			switch strings.ToLower(strings.TrimSpace(cell)) {
+			case "present", "yes", "y", "o", "1", "true":
+			case "absent", "no", "n", "x", "0", "false", "":
+				a.setAbsent(line[nameIndex], r)
+			default:
+				return nil, fmt.Errorf("invalid availability of %s in round %d: %s", line[nameIndex], r+1, cell)
+			}
+			r++
+		}
+	}
+	return a, nil
+}
+
+// Present returns true if the member attends 0-based round r
+func (a *Availability) Present(name string, r int) bool {
+	if a == nil {
+		return true
+	}
+	return !a.absent[name][r]
+}
+
+// validate returns error if availability has members who are not included in members
+func (a *Availability) validate(members []*Member) error {
+	if a == nil {
+		return nil
+	}
+	names := map[string]bool{}
+	for _, member := range members {
+		names[member.Name] = true
+	}
+	for name := range a.absent {
+		if !names[name] {
+			return fmt.Errorf("unknown member in availability: %s", name)
+		}
+	}
+	return nil
+}
+
+// attendees returns indexes of members who attend 0-based round r
+func (a *Availability) attendees(members []*Member, r int) (indexes []int) {
+	for m, member := range members {
+		if a.Present(member.Name, r) {
+			indexes = append(indexes, m)
+		}
+	}
+	return
+}
+
+// fullRoundNum returns number of leading rounds from round `from` which every member attends, up to roundNum
+func (a *Availability) fullRoundNum(members []*Member, from, roundNum int) int {
+	for r := 0; r < roundNum; r++ {
+		if len(a.attendees(members, from+r)) != len(members) {
+			return r
+		}
+	}
+	return roundNum
+}
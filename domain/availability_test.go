@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+)
+
+func Test_parseAvailabilityLines(t *testing.T) {
+	tests := []struct {
+		name        string
+		lines       [][]string
+		wantAbsent  map[string][]int
+		wantPresent map[string][]int
+		wantErr     bool
+	}{
+		{
+			name: "various cells",
+			lines: [][]string{
+				{"NAME", "1st", "2nd", "3rd"},
+				{"alice", "present", "absent", ""},
+				{"bob", "o", "x", "1"},
+				{"carol", "Yes", "No", "0"},
+			},
+			wantAbsent:  map[string][]int{"alice": {1, 2}, "bob": {1}, "carol": {1, 2}},
+			wantPresent: map[string][]int{"alice": {0, 3}, "bob": {0, 2}, "carol": {0}, "dave": {0, 1, 2}},
+		},
+		{
+			name:    "unknown cell",
+			lines:   [][]string{{"NAME", "1st"}, {"alice", "maybe"}},
+			wantErr: true,
+		},
+		{
+			name:    "no NAME column",
+			lines:   [][]string{{"ID", "1st"}, {"1", "o"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseAvailabilityLines(tt.lines)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseAvailabilityLines() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for name, rounds := range tt.wantAbsent {
+				for _, r := range rounds {
+					if got.Present(name, r) {
+						t.Errorf("Present(%s, %d) = true, want false", name, r)
+					}
+				}
+			}
+			for name, rounds := range tt.wantPresent {
+				for _, r := range rounds {
+					if !got.Present(name, r) {
+						t.Errorf("Present(%s, %d) = false, want true", name, r)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateGroups_Availability(t *testing.T) {
+	members := newTestMembers("alice", "bob", "carol", "dave", "ellen", "frank", "grace", "heidi", "ivan")
+	availability := newAvailability([][]string{nil, {"alice", "bob"}, {"carol"}})
+	got, err := GenerateGroups(context.Background(), members, availability, 3, NewGroupSizes(3), 10, nil, NewAnnealingSolver(10000), rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatalf("GenerateGroups() error = %v", err)
+	}
+	for r, groups := range got {
+		attendees := groups.groupIDMap()
+		for _, member := range members {
+			if _, ok := attendees[member.Name]; ok != availability.Present(member.Name, r) {
+				t.Errorf("GenerateGroups() assignment of %s in round %d = %t, want %t", member.Name, r+1, ok, !ok)
+			}
+		}
+	}
+	if dup, _ := CountDupMemberPairs(got); dup != 0 {
+		t.Errorf("GenerateGroups() dup = %v, want 0", dup)
+	}
+
+	unknown := newAvailability([][]string{{"zoe"}})
+	if _, err := GenerateGroups(context.Background(), members, unknown, 1, NewGroupSizes(3), 10, nil, &GreedySolver{}, rand.New(rand.NewSource(1))); err == nil {
+		t.Errorf("GenerateGroups() must fail if availability has unknown member")
+	}
+}
+
+func TestGenerateNextGroups_Availability(t *testing.T) {
+	members, history, err := parseGroupLinesWithMembers([][]string{
+		{"NAME", "1st", "2nd"},
+		{"alice", "1", ""},
+		{"bob", "1", "1"},
+		{"carol", "2", "1"},
+		{"dave", "2", "2"},
+		{"ellen", "", "2"},
+	})
+	if err != nil {
+		t.Fatalf("failed to parse group lines: %v", err)
+	}
+	availability := newAvailability([][]string{nil, nil, {"bob"}})
+	got, err := GenerateNextGroups(context.Background(), members, history, availability, NewGroupSizes(2), 10, nil, NewAnnealingSolver(10000), rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatalf("GenerateNextGroups() error = %v", err)
+	}
+	attendees := got.groupIDMap()
+	if _, ok := attendees["bob"]; ok || len(attendees) != 4 {
+		t.Errorf("GenerateNextGroups() = %v, want groups of 4 members except bob", attendees)
+	}
+}
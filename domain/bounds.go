@@ -0,0 +1,230 @@
+package domain
+
+import "fmt"
+
+// Bounds is theoretical bounds of schedules which divide members into groups of given capacities in every round
+type Bounds struct {
+	// MaxRepeatFreeRoundNum is upper bound of number of rounds in which no pair of members meets twice
+	MaxRepeatFreeRoundNum int
+	// Design achieves MaxRepeatFreeRoundNum if it is not nil
+	Design *Design
+}
+
+// NewBounds calculates bounds of schedules which divide members into groups of capacities
+func NewBounds(capacities []int) (*Bounds, error) {
+	memberNum := sum(capacities)
+	if len(capacities) == 0 || memberNum < 2 {
+		return nil, fmt.Errorf("at least 2 members are required")
+	}
+	for _, capacity := range capacities {
+		if capacity < 1 {
+			return nil, fmt.Errorf("group capacity must be positive: %d", capacity)
+		}
+	}
+
+	minCapacity, maxCapacity := capacities[0], capacities[0]
+	for _, capacity := range capacities {
+		if capacity < minCapacity {
+			minCapacity = capacity
+		}
+		if capacity > maxCapacity {
+			maxCapacity = capacity
+		}
+	}
+
+	bounds := &Bounds{}
+	switch {
+	case maxCapacity == 1:
+		// nobody meets anyone, so any number of rounds is repeat free
+		return nil, fmt.Errorf("every group has only one member")
+	case maxCapacity > len(capacities):
+		// a group of the second round has two members from the same group of the first round by pigeonhole principle
+		bounds.MaxRepeatFreeRoundNum = 1
+	case minCapacity == 1:
+		// a member of a group of one member meets nobody, so only the total number of pairs bounds rounds
+		bounds.MaxRepeatFreeRoundNum = PairNum(memberNum) / roundPairNum(capacities)
+	default:
+		// each member meets at least minCapacity-1 new members in each round
+		bounds.MaxRepeatFreeRoundNum = (memberNum - 1) / (minCapacity - 1)
+		if n := PairNum(memberNum) / roundPairNum(capacities); n < bounds.MaxRepeatFreeRoundNum {
+			bounds.MaxRepeatFreeRoundNum = n
+		}
+	}
+
+	if minCapacity == maxCapacity {
+		if design, ok := FindDesign(memberNum, minCapacity); ok && design.MaxRoundNum() == bounds.MaxRepeatFreeRoundNum {
+			bounds.Design = design
+		}
+	}
+	return bounds, nil
+}
+
+// DupLowerBound returns lower bound of number of duplicated member pairs of schedules
+// which divide members into groups of capacitiesList[r] in round r
+func DupLowerBound(capacitiesList [][]int) int {
+	if len(capacitiesList) == 0 {
+		return 0
+	}
+	memberNum := sum(capacitiesList[0])
+
+	// every pair meeting beyond the number of pairs is duplicated
+	meetings := 0
+	for _, capacities := range capacitiesList {
+		meetings += roundPairNum(capacities)
+	}
+	globalBound := meetings - PairNum(memberNum)
+
+	// each member meets at least the smallest capacity - 1 members in each round
+	need := -(memberNum - 1)
+	for _, capacities := range capacitiesList {
+		minCapacity := capacities[0]
+		for _, capacity := range capacities {
+			if capacity < minCapacity {
+				minCapacity = capacity
+			}
+		}
+		need += minCapacity - 1
+	}
+	// each duplicated pair is counted from both members
+	memberBound := (memberNum*need + 1) / 2
+
+	// members of a group are spread over groups of every other round at best.
+	// a pair which meets c times is counted c*(c-1)/2 times as a pair of rounds and duplicated c-1 times,
+	// and c-1 >= c*(c-1)/2 * 2/roundNum holds because c <= roundNum.
+	overlaps := 0
+	for i, capacities := range capacitiesList {
+		for _, other := range capacitiesList[i+1:] {
+			for _, capacity := range other {
+				overlaps += minOverlap(capacity, len(capacities))
+			}
+		}
+	}
+	roundNum := len(capacitiesList)
+	overlapBound := (2*overlaps + roundNum - 1) / roundNum
+
+	bound := 0
+	for _, b := range []int{globalBound, memberBound, overlapBound} {
+		if b > bound {
+			bound = b
+		}
+	}
+	return bound
+}
+
+// DupLowerBound returns lower bound of number of duplicated member pairs of schedules
+// in which the same members as s attend each round and groups have the same capacities as s.
+// It is equal to DupLowerBound of capacities of s if every member attends every round,
+// and only members who attend the same rounds are assumed to meet otherwise.
+func (s *Schedule) DupLowerBound() int {
+	attends := make([][]bool, len(s.rounds))
+	for r, rd := range s.rounds {
+		attends[r] = make([]bool, len(s.members))
+		for m, g := range rd.groupIndex {
+			attends[r][m] = g != -1
+		}
+	}
+	capacitiesList := s.CapacitiesList()
+
+	// every pair meeting beyond the number of pairs who attend a common round is duplicated
+	meetings, possiblePairs := 0, 0
+	for _, capacities := range capacitiesList {
+		meetings += roundPairNum(capacities)
+	}
+	// partners[m] is number of members who attend a round with member m
+	partners := make([]int, len(s.members))
+	for a := range s.members {
+		for b := a + 1; b < len(s.members); b++ {
+			for r := range s.rounds {
+				if attends[r][a] && attends[r][b] {
+					possiblePairs++
+					partners[a]++
+					partners[b]++
+					break
+				}
+			}
+		}
+	}
+	globalBound := meetings - possiblePairs
+
+	// each member meets at least the smallest capacity - 1 members in each round which the member attends
+	total := 0
+	for m := range s.members {
+		need := -partners[m]
+		for r, capacities := range capacitiesList {
+			if attends[r][m] {
+				need += minInts(capacities) - 1
+			}
+		}
+		if need > 0 {
+			total += need
+		}
+	}
+	// each duplicated pair is counted from both members
+	memberBound := (total + 1) / 2
+
+	// overlaps of groups are counted only between rounds which the same members attend, as DupLowerBound does
+	overlaps := 0
+	for i, capacities := range capacitiesList {
+		for j := i + 1; j < len(capacitiesList); j++ {
+			if !sameAttendance(attends[i], attends[j]) {
+				continue
+			}
+			for _, capacity := range capacitiesList[j] {
+				overlaps += minOverlap(capacity, len(capacities))
+			}
+		}
+	}
+	overlapBound := 0
+	if roundNum := len(capacitiesList); roundNum > 0 {
+		overlapBound = (2*overlaps + roundNum - 1) / roundNum
+	}
+
+	bound := 0
+	for _, b := range []int{globalBound, memberBound, overlapBound} {
+		if b > bound {
+			bound = b
+		}
+	}
+	return bound
+}
+
+func sameAttendance(a, b []bool) bool {
+	for m := range a {
+		if a[m] != b[m] {
+			return false
+		}
+	}
+	return true
+}
+
+// minInts returns the smallest value of values, or 0 if values is empty
+func minInts(values []int) int {
+	if len(values) == 0 {
+		return 0
+	}
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
+
+// minOverlap returns minimum number of pairs in a group of capacity members which were in same group of groupNum groups
+func minOverlap(capacity, groupNum int) int {
+	q, rem := capacity/groupNum, capacity%groupNum
+	return rem*PairNum(q+1) + (groupNum-rem)*PairNum(q)
+}
+
+// PairNum returns number of all possible pairs of memberNum members
+func PairNum(memberNum int) int {
+	return memberNum * (memberNum - 1) / 2
+}
+
+func roundPairNum(capacities []int) (n int) {
+	for _, capacity := range capacities {
+		n += PairNum(capacity)
+	}
+	return
+}
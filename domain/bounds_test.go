@@ -0,0 +1,111 @@
+package domain
+
+import "testing"
+
+func TestDupLowerBound(t *testing.T) {
+	tests := []struct {
+		name           string
+		capacitiesList [][]int
+		want           int
+	}{
+		{name: "no rounds", capacitiesList: nil, want: 0},
+		{name: "repeat free", capacitiesList: [][]int{{2, 2}, {2, 2}, {2, 2}}, want: 0},
+		{name: "more rounds than pairs", capacitiesList: [][]int{{2, 2}, {2, 2}, {2, 2}, {2, 2}}, want: 2},
+		{name: "each member meets too many members", capacitiesList: [][]int{{4, 4}, {4, 4}, {4, 4}}, want: 8},
+		{name: "groups are larger than number of groups", capacitiesList: [][]int{{4, 4, 4}, {4, 4, 4}, {4, 4, 4}}, want: 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DupLowerBound(tt.capacitiesList); got != tt.want {
+				t.Errorf("DupLowerBound() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchedule_DupLowerBound(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines [][]string
+		want  int
+	}{
+		{
+			name: "every member attends",
+			lines: [][]string{
+				{"NAME", "1st", "2nd", "3rd", "4th"},
+				{"alice", "1", "1", "1", "1"},
+				{"bob", "1", "1", "2", "2"},
+				{"carol", "2", "2", "1", "2"},
+				{"dave", "2", "2", "2", "1"},
+			},
+			// same as DupLowerBound of [][]int{{2, 2}, {2, 2}, {2, 2}, {2, 2}}
+			want: 2,
+		},
+		{
+			// only alice and bob attend the first round, so the bound must not assume 2 members in every round
+			name: "members join later",
+			lines: [][]string{
+				{"NAME", "1st", "2nd", "3rd"},
+				{"alice", "1", "1", "1"},
+				{"bob", "1", "2", "2"},
+				{"carol", "", "1", "1"},
+				{"dave", "", "1", "2"},
+				{"ellen", "", "2", "1"},
+				{"frank", "", "2", "2"},
+			},
+			want: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			members, groupsList, err := parseGroupLinesWithMembers(tt.lines)
+			if err != nil {
+				t.Fatalf("failed to parse groups: %v", err)
+			}
+			s, err := NewScheduleFromGroupsList(members, groupsList)
+			if err != nil {
+				t.Fatalf("failed to create schedule: %v", err)
+			}
+			got := s.DupLowerBound()
+			if got != tt.want {
+				t.Errorf("DupLowerBound() = %v, want %v", got, tt.want)
+			}
+			if dup := s.CountDup(); got > dup {
+				t.Errorf("DupLowerBound() = %v must not exceed duplicated member pairs %v", got, dup)
+			}
+		})
+	}
+}
+
+func TestNewBounds(t *testing.T) {
+	tests := []struct {
+		name       string
+		capacities []int
+		want       int
+		wantDesign bool
+		wantErr    bool
+	}{
+		{name: "affine plane", capacities: []int{4, 4, 4, 4}, want: 5, wantDesign: true},
+		{name: "round robin", capacities: []int{2, 2, 2}, want: 5, wantDesign: true},
+		{name: "groups are larger than number of groups", capacities: []int{4, 4, 4}, want: 1},
+		{name: "uneven groups", capacities: []int{3, 3, 2}, want: 4},
+		{name: "single members", capacities: []int{1, 1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewBounds(tt.capacities)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewBounds() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.MaxRepeatFreeRoundNum != tt.want {
+				t.Errorf("NewBounds() MaxRepeatFreeRoundNum = %v, want %v", got.MaxRepeatFreeRoundNum, tt.want)
+			}
+			if (got.Design != nil) != tt.wantDesign {
+				t.Errorf("NewBounds() Design = %v, wantDesign %v", got.Design, tt.wantDesign)
+			}
+		})
+	}
+}
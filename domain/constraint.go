@@ -0,0 +1,522 @@
+package domain
+
+import (
+	"encoding/csv"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// PairConstraintType is type of PairConstraint
+type PairConstraintType string
+
+// Types of PairConstraint
+const (
+	// Together means that the members must be in the same group
+	Together PairConstraintType = "together"
+	// Apart means that the members must not be in the same group
+	Apart PairConstraintType = "apart"
+)
+
+// PairConstraint is a constraint on two members which applies to every round they both attend
+type PairConstraint struct {
+	Type  PairConstraintType
+	Names [2]string
+	// Weight is penalty of a violation of soft constraint. The constraint is hard if Weight is 0.
+	Weight float64
+}
+
+func (p *PairConstraint) String() string {
+	return fmt.Sprintf("%s and %s must be %s", p.Names[0], p.Names[1], p.Type)
+}
+
+// RoleConstraintType is type of RoleConstraint
+type RoleConstraintType string
+
+// Types of RoleConstraint
+const (
+	// RoleMin means that each group must have at least Count members of the role
+	RoleMin RoleConstraintType = "min"
+	// RoleMax means that each group must have at most Count members of the role
+	RoleMax RoleConstraintType = "max"
+)
+
+// RoleConstraint is a constraint on number of members of a role in each group
+type RoleConstraint struct {
+	Type  RoleConstraintType
+	Role  string
+	Count int
+	// Weight is penalty of each missing or extra member of soft constraint. The constraint is hard if Weight is 0.
+	Weight float64
+}
+
+func (c *RoleConstraint) String() string {
+	if c.Type == RoleMin {
+		return fmt.Sprintf("each group must have at least %d %s", c.Count, c.Role)
+	}
+	return fmt.Sprintf("each group must have at most %d %s", c.Count, c.Role)
+}
+
+// excess returns how far n members of the role are from satisfying the constraint
+func (c *RoleConstraint) excess(n int) int {
+	if c.Type == RoleMin && n < c.Count {
+		return c.Count - n
+	}
+	if c.Type == RoleMax && n > c.Count {
+		return n - c.Count
+	}
+	return 0
+}
+
+// Pin is a constraint which assigns a member to a group in a round
+type Pin struct {
+	Name string
+	// Round is 1-based index of the round
+	Round   int
+	GroupID GroupID
+}
+
+func (p *Pin) String() string {
+	return fmt.Sprintf("%s must be in group %d", p.Name, p.GroupID)
+}
+
+// NewPins returns pins which assign each member of groupsList to the group in the round.
+// Group IDs must be positive as in constraint files.
+func NewPins(groupsList []Groups) (pins []*Pin, err error) {
+	for r, groups := range groupsList {
+		for _, id := range groups.sortedIDList() {
+			for _, member := range groups[id].members {
+				if id < 1 {
+					return nil, fmt.Errorf("group ID of %s in round %d must be a positive integer: %d", member.Name, r+1, id)
+				}
+				pins = append(pins, &Pin{Name: member.Name, Round: r + 1, GroupID: id})
+			}
+		}
+	}
+	return
+}
+
+// Constraints are rules which groups must satisfy
+type Constraints struct {
+	Pairs []*PairConstraint
+	Pins  []*Pin
+	Roles []*RoleConstraint
+}
+
+// Violation is a constraint which is not satisfied by a group
+type Violation struct {
+	// Round is 1-based index of the round
+	Round   int
+	GroupID GroupID
+	Message string
+	// Weight is weight of the violated constraint if it is soft, or 0 if it is hard
+	Weight float64
+}
+
+func (v *Violation) String() string {
+	if v.Weight > 0 {
+		return fmt.Sprintf("round %d, group %d: %s (soft, weight %g)", v.Round, v.GroupID, v.Message, v.Weight)
+	}
+	return fmt.Sprintf("round %d, group %d: %s", v.Round, v.GroupID, v.Message)
+}
+
+// penalty returns weight of a soft constraint, or 1 for a hard constraint which is weighted by Objective later
+func penalty(weight float64) float64 {
+	if weight > 0 {
+		return weight
+	}
+	return 1
+}
+
+// ParseConstraintFile parses constraint csv file.
+// Each line has type of the constraint followed by its arguments, such as "together,alice,bob" or "apart,alice,bob".
+// "pin,alice,2,3" assigns alice to group 3 in round 2.
+// "min,facilitator,1" and "max,manager,2" limit number of members of the role in each group.
+// Pair and role constraints are soft if they have "soft" or "soft:WEIGHT" as the last field, such as "apart,alice,bob,soft:2".
+func ParseConstraintFile(filePath string) (*Constraints, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file from %s", filePath)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.Comment = commentPrefix
+	// number of fields depends on type of constraint
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	lines, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse csv from %s: %w", filePath, err)
+	}
+	return parseConstraintLines(lines)
+}
+
+func parseConstraintLines(lines [][]string) (*Constraints, error) {
+	constraints := &Constraints{}
+	for i, line := range lines {
+		line, weight, err := parseSoftWeight(line)
+		if err != nil {
+			return nil, fmt.Errorf("invalid soft constraint at line %d: %w", i+1, err)
+		}
+		switch constraintType := strings.ToLower(line[0]); constraintType {
+		case string(Together), string(Apart):
+			if len(line) != 3 {
+				return nil, fmt.Errorf("%s constraint must have two names at line %d: %s", constraintType, i+1, line)
+			}
+			if line[1] == line[2] {
+				return nil, fmt.Errorf("%s constraint must have different names at line %d: %s", constraintType, i+1, line)
+			}
+			constraints.Pairs = append(constraints.Pairs, &PairConstraint{
+				Type:   PairConstraintType(constraintType),
+				Names:  [2]string{line[1], line[2]},
+				Weight: weight,
+			})
+		case "pin":
+			if weight > 0 {
+				return nil, fmt.Errorf("pin constraint can not be soft at line %d", i+1)
+			}
+			if len(line) != 4 {
+				return nil, fmt.Errorf("pin constraint must have a name, a round and a group ID at line %d: %s", i+1, line)
+			}
+			r, err := strconv.Atoi(line[2])
+			if err != nil || r < 1 {
+				return nil, fmt.Errorf("round of pin constraint must be a positive integer at line %d: %s", i+1, line[2])
+			}
+			id, err := strconv.Atoi(line[3])
+			if err != nil || id < 1 {
+				return nil, fmt.Errorf("group ID of pin constraint must be a positive integer at line %d: %s", i+1, line[3])
+			}
+			constraints.Pins = append(constraints.Pins, &Pin{Name: line[1], Round: r, GroupID: GroupID(id)})
+		case string(RoleMin), string(RoleMax):
+			if len(line) != 3 {
+				return nil, fmt.Errorf("%s constraint must have a role and a count at line %d: %s", constraintType, i+1, line)
+			}
+			count, err := strconv.Atoi(line[2])
+			if err != nil || count < 0 || (constraintType == string(RoleMin) && count == 0) {
+				return nil, fmt.Errorf("count of %s constraint is invalid at line %d: %s", constraintType, i+1, line[2])
+			}
+			constraints.Roles = append(constraints.Roles, &RoleConstraint{Type: RoleConstraintType(constraintType), Role: line[1], Count: count, Weight: weight})
+		default:
+			return nil, fmt.Errorf("unknown constraint type at line %d: %s", i+1, line[0])
+		}
+	}
+	return constraints, nil
+}
+
+// parseSoftWeight removes soft field from the end of line and returns its weight, or 0 if line does not have soft field
+func parseSoftWeight(line []string) ([]string, float64, error) {
+	last := strings.ToLower(line[len(line)-1])
+	if len(line) < 2 || (last != "soft" && !strings.HasPrefix(last, "soft:")) {
+		return line, 0, nil
+	}
+	if last == "soft" {
+		return line[:len(line)-1], 1, nil
+	}
+	weight, err := strconv.ParseFloat(strings.TrimPrefix(last, "soft:"), 64)
+	if err != nil || !(weight > 0) || math.IsInf(weight, 0) {
+		return nil, 0, fmt.Errorf("weight of soft constraint must be a positive number: %s", line[len(line)-1])
+	}
+	return line[:len(line)-1], weight, nil
+}
+
+// Violations returns constraints which groupsList does not satisfy in order of rounds
+func (c *Constraints) Violations(groupsList []Groups) (violations []*Violation) {
+	if c == nil {
+		return nil
+	}
+	for r, groups := range groupsList {
+		groupIDs := groups.groupIDMap()
+		for _, pair := range c.Pairs {
+			id0, ok0 := groupIDs[pair.Names[0]]
+			id1, ok1 := groupIDs[pair.Names[1]]
+			if !ok0 || !ok1 || (id0 == id1) == (pair.Type == Together) {
+				continue
+			}
+			violations = append(violations, &Violation{
+				Round:   r + 1,
+				GroupID: id0,
+				Message: fmt.Sprintf("%s (%s is in group %d)", pair, pair.Names[1], id1),
+				Weight:  pair.Weight,
+			})
+		}
+		for _, pin := range c.Pins {
+			if pin.Round != r+1 {
+				continue
+			}
+			id, ok := groupIDs[pin.Name]
+			if ok && id == pin.GroupID {
+				continue
+			}
+			message := fmt.Sprintf("%s (%s is absent)", pin, pin.Name)
+			if ok {
+				message = fmt.Sprintf("%s (%s is in group %d)", pin, pin.Name, id)
+			}
+			violations = append(violations, &Violation{Round: r + 1, GroupID: pin.GroupID, Message: message})
+		}
+		for _, id := range groups.sortedIDList() {
+			for _, role := range c.Roles {
+				n := 0
+				for _, member := range groups[id].members {
+					if member.HasRole(role.Role) {
+						n++
+					}
+				}
+				if role.excess(n) > 0 {
+					violations = append(violations, &Violation{
+						Round:   r + 1,
+						GroupID: id,
+						Message: fmt.Sprintf("%s (group has %d)", role, n),
+						Weight:  role.Weight,
+					})
+				}
+			}
+		}
+	}
+	return
+}
+
+// Empty returns true if there is no constraint
+func (c *Constraints) Empty() bool {
+	return c == nil || (len(c.Pairs) == 0 && len(c.Pins) == 0 && len(c.Roles) == 0)
+}
+
+// partition returns hard constraints and soft constraints of c. Pins are always hard.
+func (c *Constraints) partition() (hard, soft *Constraints) {
+	hard, soft = &Constraints{}, &Constraints{}
+	if c == nil {
+		return
+	}
+	hard.Pins = c.Pins
+	for _, pair := range c.Pairs {
+		if pair.Weight > 0 {
+			soft.Pairs = append(soft.Pairs, pair)
+		} else {
+			hard.Pairs = append(hard.Pairs, pair)
+		}
+	}
+	for _, role := range c.Roles {
+		if role.Weight > 0 {
+			soft.Roles = append(soft.Roles, role)
+		} else {
+			hard.Roles = append(hard.Roles, role)
+		}
+	}
+	return
+}
+
+// Add appends constraints of other to c
+func (c *Constraints) Add(other *Constraints) {
+	c.Pairs = append(c.Pairs, other.Pairs...)
+	c.Pins = append(c.Pins, other.Pins...)
+	c.Roles = append(c.Roles, other.Roles...)
+}
+
+// pinnedGroups returns 0-based group index of pinned members in each 0-based round.
+// pinnedGroups[r][m] is the group of member m in round r.
+func (c *Constraints) pinnedGroups(members []*Member) (map[int]map[int]int, error) {
+	if c == nil {
+		return nil, nil
+	}
+	indexes := map[string]int{}
+	for i, member := range members {
+		indexes[member.Name] = i
+	}
+	pinned := map[int]map[int]int{}
+	for _, pin := range c.Pins {
+		m, ok := indexes[pin.Name]
+		if !ok {
+			return nil, fmt.Errorf("unknown member in constraint(%s in round %d): %s", pin, pin.Round, pin.Name)
+		}
+		r := pin.Round - 1
+		if _, ok := pinned[r]; !ok {
+			pinned[r] = map[int]int{}
+		}
+		if g, ok := pinned[r][m]; ok && g != int(pin.GroupID)-1 {
+			return nil, fmt.Errorf("%s is pinned to group %d and group %d in round %d", pin.Name, g+1, pin.GroupID, pin.Round)
+		}
+		pinned[r][m] = int(pin.GroupID) - 1
+	}
+	return pinned, nil
+}
+
+// pairConstraintCost is a Cost which counts violations of pair constraints
+type pairConstraintCost struct {
+	// partners has constraints of each member
+	partners [][]pairPartner
+}
+
+type pairPartner struct {
+	other    int
+	together bool
+	penalty  float64
+}
+
+func newPairConstraintCost(members []*Member, pairs []*PairConstraint) (*pairConstraintCost, error) {
+	indexes := map[string]int{}
+	for i, member := range members {
+		indexes[member.Name] = i
+	}
+	c := &pairConstraintCost{partners: make([][]pairPartner, len(members))}
+	for _, pair := range pairs {
+		a, ok := indexes[pair.Names[0]]
+		if !ok {
+			return nil, fmt.Errorf("unknown member in constraint(%s): %s", pair, pair.Names[0])
+		}
+		b, ok := indexes[pair.Names[1]]
+		if !ok {
+			return nil, fmt.Errorf("unknown member in constraint(%s): %s", pair, pair.Names[1])
+		}
+		together, p := pair.Type == Together, penalty(pair.Weight)
+		c.partners[a] = append(c.partners[a], pairPartner{other: b, together: together, penalty: p})
+		c.partners[b] = append(c.partners[b], pairPartner{other: a, together: together, penalty: p})
+	}
+	return c, nil
+}
+
+func violatesPair(ga, gb int, together bool) bool {
+	if ga == -1 || gb == -1 {
+		return false
+	}
+	return (ga == gb) != together
+}
+
+// Cost returns number of violations in all rounds, in which violations of soft constraints are multiplied by their weights
+func (c *pairConstraintCost) Cost(s *Schedule) float64 {
+	total := 0.0
+	for _, rd := range s.rounds {
+		for m, partners := range c.partners {
+			for _, p := range partners {
+				if m < p.other && violatesPair(rd.groupIndex[m], rd.groupIndex[p.other], p.together) {
+					total += p.penalty
+				}
+			}
+		}
+	}
+	return total
+}
+
+// roundRange returns sum of penalties, because each constraint is violated at most once in a round
+func (c *pairConstraintCost) roundRange(memberNum int) (total float64) {
+	for m, partners := range c.partners {
+		for _, p := range partners {
+			if m < p.other {
+				total += p.penalty
+			}
+		}
+	}
+	return
+}
+
+// SwapDelta returns difference of Cost caused by swap
+func (c *pairConstraintCost) SwapDelta(s *Schedule, r, a, b int) float64 {
+	rd := s.rounds[r]
+	after := func(m int) int {
+		switch m {
+		case a:
+			return rd.groupIndex[b]
+		case b:
+			return rd.groupIndex[a]
+		}
+		return rd.groupIndex[m]
+	}
+	delta := 0.0
+	for _, m := range []int{a, b} {
+		for _, p := range c.partners[m] {
+			// a constraint between a and b is counted once
+			if m == b && p.other == a {
+				continue
+			}
+			if violatesPair(after(m), after(p.other), p.together) {
+				delta += p.penalty
+			}
+			if violatesPair(rd.groupIndex[m], rd.groupIndex[p.other], p.together) {
+				delta -= p.penalty
+			}
+		}
+	}
+	return delta
+}
+
+// roleConstraintCost is a Cost which counts how far groups are from satisfying role constraints
+type roleConstraintCost struct {
+	constraints []*RoleConstraint
+	// has[c][m] is true if member m has the role of constraints[c]
+	has [][]bool
+}
+
+func newRoleConstraintCost(members []*Member, constraints []*RoleConstraint) (*roleConstraintCost, error) {
+	c := &roleConstraintCost{constraints: constraints, has: make([][]bool, len(constraints))}
+	for i, constraint := range constraints {
+		c.has[i] = make([]bool, len(members))
+		found := false
+		for m, member := range members {
+			c.has[i][m] = member.HasRole(constraint.Role)
+			found = found || c.has[i][m]
+		}
+		if !found && constraint.Type == RoleMin {
+			return nil, fmt.Errorf("no member has role of constraint(%s): %s", constraint, constraint.Role)
+		}
+	}
+	return c, nil
+}
+
+func (c *roleConstraintCost) count(i int, group []int) (n int) {
+	for _, m := range group {
+		if c.has[i][m] {
+			n++
+		}
+	}
+	return
+}
+
+// Cost returns sum of excess of all groups in all rounds, in which excess of soft constraints is multiplied by their weights
+func (c *roleConstraintCost) Cost(s *Schedule) float64 {
+	total := 0.0
+	for _, rd := range s.rounds {
+		for _, group := range rd.groups {
+			for i, constraint := range c.constraints {
+				total += penalty(constraint.Weight) * float64(constraint.excess(c.count(i, group)))
+			}
+		}
+	}
+	return total
+}
+
+// roundRange returns sum of penalties of the largest excess in a round.
+// Excess of a max constraint is at most size of the group, and that of a min constraint is at most its count in each group.
+func (c *roleConstraintCost) roundRange(memberNum int) (total float64) {
+	for _, constraint := range c.constraints {
+		count := constraint.Count
+		if count < 1 {
+			count = 1
+		}
+		total += penalty(constraint.Weight) * float64(memberNum*count)
+	}
+	return
+}
+
+// SwapDelta returns difference of excess caused by swap. Only constraints on a role which either a or b has change.
+func (c *roleConstraintCost) SwapDelta(s *Schedule, r, a, b int) float64 {
+	rd := s.rounds[r]
+	ga, gb := rd.groupIndex[a], rd.groupIndex[b]
+	if ga == gb {
+		return 0
+	}
+	delta := 0.0
+	for i, constraint := range c.constraints {
+		if c.has[i][a] == c.has[i][b] {
+			continue
+		}
+		na, nb := c.count(i, rd.groups[ga]), c.count(i, rd.groups[gb])
+		// the member who has the role moves from one group to the other
+		diff := 1
+		if c.has[i][b] {
+			diff = -1
+		}
+		delta += penalty(constraint.Weight) * float64(constraint.excess(na-diff)+constraint.excess(nb+diff)-constraint.excess(na)-constraint.excess(nb))
+	}
+	return delta
+}
@@ -0,0 +1,396 @@
+package domain
+
+import (
+	"context"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_parseConstraintLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		lines   [][]string
+		want    *Constraints
+		wantErr bool
+	}{
+		{
+			name:  "together and apart",
+			lines: [][]string{{"together", "alice", "bob"}, {"Apart", "carol", "dave"}},
+			want: &Constraints{Pairs: []*PairConstraint{
+				{Type: Together, Names: [2]string{"alice", "bob"}},
+				{Type: Apart, Names: [2]string{"carol", "dave"}},
+			}},
+		},
+		{
+			name:  "pin",
+			lines: [][]string{{"pin", "alice", "2", "3"}},
+			want:  &Constraints{Pins: []*Pin{{Name: "alice", Round: 2, GroupID: 3}}},
+		},
+		{
+			name:  "roles",
+			lines: [][]string{{"min", "facilitator", "1"}, {"MAX", "manager", "2"}},
+			want: &Constraints{Roles: []*RoleConstraint{
+				{Type: RoleMin, Role: "facilitator", Count: 1},
+				{Type: RoleMax, Role: "manager", Count: 2},
+			}},
+		},
+		{
+			name:  "soft constraints",
+			lines: [][]string{{"apart", "alice", "bob", "soft"}, {"max", "manager", "1", "SOFT:2.5"}},
+			want: &Constraints{
+				Pairs: []*PairConstraint{{Type: Apart, Names: [2]string{"alice", "bob"}, Weight: 1}},
+				Roles: []*RoleConstraint{{Type: RoleMax, Role: "manager", Count: 1, Weight: 2.5}},
+			},
+		},
+		{name: "soft pin", lines: [][]string{{"pin", "alice", "1", "1", "soft"}}, wantErr: true},
+		{name: "non-positive soft weight", lines: [][]string{{"apart", "alice", "bob", "soft:0"}}, wantErr: true},
+		{name: "invalid soft weight", lines: [][]string{{"apart", "alice", "bob", "soft:high"}}, wantErr: true},
+		{name: "min role count of 0", lines: [][]string{{"min", "facilitator", "0"}}, wantErr: true},
+		{name: "negative max role count", lines: [][]string{{"max", "manager", "-1"}}, wantErr: true},
+		{name: "role without count", lines: [][]string{{"max", "manager"}}, wantErr: true},
+		{name: "pin without group", lines: [][]string{{"pin", "alice", "2"}}, wantErr: true},
+		{name: "pin to round 0", lines: [][]string{{"pin", "alice", "0", "1"}}, wantErr: true},
+		{name: "pin to non-integer group", lines: [][]string{{"pin", "alice", "1", "a"}}, wantErr: true},
+		{name: "unknown type", lines: [][]string{{"near", "alice", "bob"}}, wantErr: true},
+		{name: "missing name", lines: [][]string{{"together", "alice"}}, wantErr: true},
+		{name: "same name", lines: [][]string{{"apart", "alice", "alice"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseConstraintLines(tt.lines)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseConstraintLines() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseConstraintLines() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConstraints_Violations(t *testing.T) {
+	groupsList, err := parseGroupLines([][]string{
+		{"NAME", "1st", "2nd"},
+		{"alice", "1", "1"},
+		{"bob", "1", "2"},
+		{"carol", "2", "1"},
+		{"dave", "2", "2"},
+	})
+	if err != nil {
+		t.Fatalf("failed to parse group lines: %v", err)
+	}
+	constraints := &Constraints{Pairs: []*PairConstraint{
+		{Type: Together, Names: [2]string{"alice", "bob"}},
+		{Type: Apart, Names: [2]string{"bob", "dave"}},
+	}, Pins: []*Pin{
+		{Name: "carol", Round: 1, GroupID: 2},
+		{Name: "carol", Round: 2, GroupID: 2},
+		{Name: "zoe", Round: 2, GroupID: 1},
+	}}
+	var got []string
+	for _, v := range constraints.Violations(groupsList) {
+		got = append(got, v.String())
+	}
+	want := []string{
+		"round 2, group 1: alice and bob must be together (bob is in group 2)",
+		"round 2, group 2: bob and dave must be apart (dave is in group 2)",
+		"round 2, group 2: carol must be in group 2 (carol is in group 1)",
+		"round 2, group 1: zoe must be in group 1 (zoe is absent)",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Violations() = %q, want %q", got, want)
+	}
+}
+
+func Test_pairConstraintCost_SwapDelta(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	s := newTestSchedule(t, 12, 4, 3, rnd)
+	var pairs []*PairConstraint
+	for i := 0; i < 6; i++ {
+		pairType := Together
+		if i%2 == 1 {
+			pairType = Apart
+		}
+		pairs = append(pairs, &PairConstraint{Type: pairType, Names: [2]string{s.members[i].Name, s.members[i+3].Name}})
+	}
+	cost, err := newPairConstraintCost(s.members, pairs)
+	if err != nil {
+		t.Fatalf("failed to create cost: %v", err)
+	}
+	assertSwapDelta(t, s, cost, rnd, 100)
+}
+
+func TestGenerateGroups_Constraints(t *testing.T) {
+	members := newTestMembers("alice", "bob", "carol", "dave", "ellen", "frank", "grace", "heidi", "ivan")
+	constraints := &Constraints{Pairs: []*PairConstraint{
+		{Type: Together, Names: [2]string{"alice", "bob"}},
+		{Type: Apart, Names: [2]string{"carol", "dave"}},
+		{Type: Apart, Names: [2]string{"alice", "ellen"}},
+	}}
+	rnd := rand.New(rand.NewSource(1))
+	got, err := GenerateGroups(context.Background(), members, nil, 4, NewGroupSizes(3), 10, &Objective{Constraints: constraints}, NewAnnealingSolver(100000), rnd)
+	if err != nil {
+		t.Fatalf("GenerateGroups() error = %v", err)
+	}
+	if violations := constraints.Violations(got); len(violations) != 0 {
+		t.Errorf("GenerateGroups() violates constraints: %v", violations)
+	}
+
+	unknown := &Constraints{Pairs: []*PairConstraint{{Type: Apart, Names: [2]string{"alice", "zoe"}}}}
+	if _, err := GenerateGroups(context.Background(), members, nil, 4, NewGroupSizes(3), 10, &Objective{Constraints: unknown}, &GreedySolver{}, rnd); err == nil {
+		t.Errorf("GenerateGroups() must fail if constraints have unknown member")
+	}
+}
+
+func TestNewPins(t *testing.T) {
+	groupsList, err := parseGroupLines([][]string{
+		{"NAME", "1st", "2nd"},
+		{"alice", "1", ""},
+		{"bob", "", "2"},
+	})
+	if err != nil {
+		t.Fatalf("failed to parse group lines: %v", err)
+	}
+	want := []*Pin{
+		{Name: "alice", Round: 1, GroupID: 1},
+		{Name: "bob", Round: 2, GroupID: 2},
+	}
+	got, err := NewPins(groupsList)
+	if err != nil {
+		t.Fatalf("NewPins() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NewPins() = %v, want %v", got, want)
+	}
+
+	invalid, err := parseGroupLines([][]string{
+		{"NAME", "1st"},
+		{"alice", "1"},
+		{"bob", "0"},
+	})
+	if err != nil {
+		t.Fatalf("failed to parse group lines: %v", err)
+	}
+	if _, err := NewPins(invalid); err == nil || !strings.Contains(err.Error(), "bob in round 1") {
+		t.Errorf("NewPins() error = %v, want error of non-positive group ID of bob in round 1", err)
+	}
+}
+
+func TestGenerateGroups_Pins(t *testing.T) {
+	members := newTestMembers("alice", "bob", "carol", "dave", "ellen", "frank", "grace", "heidi", "ivan")
+	constraints := &Constraints{Pins: []*Pin{
+		{Name: "alice", Round: 1, GroupID: 3},
+		{Name: "bob", Round: 1, GroupID: 3},
+		{Name: "carol", Round: 2, GroupID: 1},
+		{Name: "ivan", Round: 4, GroupID: 2},
+	}}
+	solvers := map[string]Solver{
+		"annealing":   NewAnnealingSolver(100000),
+		"tabu":        NewTabuSolver(1000),
+		"genetic":     NewGeneticSolver(20, 200),
+		"multi-start": NewMultiStartSolver(NewAnnealingSolver(10000), 4, 2),
+	}
+	for name, solver := range solvers {
+		t.Run(name, func(t *testing.T) {
+			rnd := rand.New(rand.NewSource(1))
+			got, err := GenerateGroups(context.Background(), members, nil, 4, NewGroupSizes(3), 10, &Objective{Constraints: constraints}, solver, rnd)
+			if err != nil {
+				t.Fatalf("GenerateGroups() error = %v", err)
+			}
+			if violations := constraints.Violations(got); len(violations) != 0 {
+				t.Errorf("GenerateGroups() moves pinned members: %v", violations)
+			}
+		})
+	}
+}
+
+func TestGenerateGroups_InvalidPins(t *testing.T) {
+	members := newTestMembers("alice", "bob", "carol", "dave", "ellen", "frank")
+	tests := []struct {
+		name string
+		pins []*Pin
+	}{
+		{name: "unknown member", pins: []*Pin{{Name: "zoe", Round: 1, GroupID: 1}}},
+		{name: "round out of range", pins: []*Pin{{Name: "alice", Round: 3, GroupID: 1}}},
+		{name: "group out of range", pins: []*Pin{{Name: "alice", Round: 1, GroupID: 3}}},
+		{name: "conflicting pins", pins: []*Pin{{Name: "alice", Round: 1, GroupID: 1}, {Name: "alice", Round: 1, GroupID: 2}}},
+		{name: "too many members", pins: []*Pin{
+			{Name: "alice", Round: 1, GroupID: 1},
+			{Name: "bob", Round: 1, GroupID: 1},
+			{Name: "carol", Round: 1, GroupID: 1},
+			{Name: "dave", Round: 1, GroupID: 1},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rnd := rand.New(rand.NewSource(1))
+			objective := &Objective{Constraints: &Constraints{Pins: tt.pins}}
+			if _, err := GenerateGroups(context.Background(), members, nil, 2, NewGroupSizes(3), 1, objective, &GreedySolver{}, rnd); err == nil {
+				t.Errorf("GenerateGroups() must fail")
+			}
+		})
+	}
+}
+
+func TestGenerateNextGroups_Pins(t *testing.T) {
+	members, history, err := parseGroupLinesWithMembers([][]string{
+		{"NAME", "1st"},
+		{"alice", "1"},
+		{"bob", "1"},
+		{"carol", "2"},
+		{"dave", "2"},
+	})
+	if err != nil {
+		t.Fatalf("failed to parse history: %v", err)
+	}
+	tests := []struct {
+		name    string
+		pins    []*Pin
+		wantErr bool
+	}{
+		{name: "new round", pins: []*Pin{{Name: "alice", Round: 2, GroupID: 2}}},
+		{name: "round of history", pins: []*Pin{{Name: "alice", Round: 1, GroupID: 2}}, wantErr: true},
+		{name: "round after new round", pins: []*Pin{{Name: "alice", Round: 3, GroupID: 2}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rnd := rand.New(rand.NewSource(1))
+			objective := &Objective{Constraints: &Constraints{Pins: tt.pins}}
+			got, err := GenerateNextGroups(context.Background(), members, history, nil, NewGroupSizes(2), 10, objective, &GreedySolver{}, rnd)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GenerateNextGroups() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.findGroupID("alice") != "2" {
+				t.Errorf("GenerateNextGroups() must put alice in group 2: %v", FormatGroupLines(members, []Groups{got}))
+			}
+		})
+	}
+}
+
+func Test_parseMemberLines_Roles(t *testing.T) {
+	members, err := parseMemberLines([][]string{
+		{"ID", "NAME", "ROLES", "department"},
+		{"1", "alice", "facilitator; manager", "sales"},
+		{"2", "bob", "", "dev"},
+	})
+	if err != nil {
+		t.Fatalf("parseMemberLines() error = %v", err)
+	}
+	if got := members[0].Roles; !reflect.DeepEqual(got, []string{"facilitator", "manager"}) {
+		t.Errorf("parseMemberLines() roles of alice = %v", got)
+	}
+	if got := members[1].Roles; got != nil {
+		t.Errorf("parseMemberLines() roles of bob = %v, want nil", got)
+	}
+	if got := AttributeNames(members); !reflect.DeepEqual(got, []string{"department"}) {
+		t.Errorf("AttributeNames() = %v, roles must not be an attribute", got)
+	}
+}
+
+func TestConstraints_Violations_Roles(t *testing.T) {
+	members := newTestMembers("alice", "bob", "carol", "dave")
+	members[0].Roles = []string{"facilitator", "manager"}
+	members[1].Roles = []string{"manager"}
+	groupsList := newGroupsList(1)
+	for m, id := range []GroupID{1, 1, 2, 2} {
+		groupsList[0].addGroup(members[m], id)
+	}
+	constraints := &Constraints{Roles: []*RoleConstraint{
+		{Type: RoleMin, Role: "facilitator", Count: 1},
+		{Type: RoleMax, Role: "manager", Count: 1},
+	}}
+	var got []string
+	for _, v := range constraints.Violations(groupsList) {
+		got = append(got, v.String())
+	}
+	want := []string{
+		"round 1, group 1: each group must have at most 1 manager (group has 2)",
+		"round 1, group 2: each group must have at least 1 facilitator (group has 0)",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Violations() = %q, want %q", got, want)
+	}
+}
+
+func Test_roleConstraintCost_SwapDelta(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	s := newTestSchedule(t, 13, 4, 4, rnd)
+	for m, member := range s.members {
+		switch m % 4 {
+		case 0:
+			member.Roles = []string{"facilitator"}
+		case 1:
+			member.Roles = []string{"manager", "facilitator"}
+		case 2:
+			member.Roles = []string{"manager"}
+		}
+	}
+	cost, err := newRoleConstraintCost(s.members, []*RoleConstraint{
+		{Type: RoleMin, Role: "facilitator", Count: 2},
+		{Type: RoleMax, Role: "manager", Count: 1, Weight: 0.5},
+	})
+	if err != nil {
+		t.Fatalf("failed to create cost: %v", err)
+	}
+	assertSwapDelta(t, s, cost, rnd, 200)
+}
+
+func TestGenerateGroups_Roles(t *testing.T) {
+	members := newTestMembers("alice", "bob", "carol", "dave", "ellen", "frank", "grace", "heidi", "ivan", "judy", "ken", "lisa")
+	for _, m := range []int{0, 4, 8} {
+		members[m].Roles = []string{"facilitator"}
+	}
+	for _, m := range []int{1, 2, 3, 5} {
+		members[m].Roles = []string{"manager"}
+	}
+	constraints := &Constraints{Roles: []*RoleConstraint{
+		{Type: RoleMin, Role: "facilitator", Count: 1},
+		{Type: RoleMax, Role: "manager", Count: 2},
+	}}
+	rnd := rand.New(rand.NewSource(1))
+	got, err := GenerateGroups(context.Background(), members, nil, 3, NewGroupSizes(4), 10, &Objective{Constraints: constraints}, NewAnnealingSolver(100000), rnd)
+	if err != nil {
+		t.Fatalf("GenerateGroups() error = %v", err)
+	}
+	if violations := constraints.Violations(got); len(violations) != 0 {
+		t.Errorf("GenerateGroups() violates constraints: %v", violations)
+	}
+
+	unknown := &Constraints{Roles: []*RoleConstraint{{Type: RoleMin, Role: "director", Count: 1}}}
+	if _, err := GenerateGroups(context.Background(), members, nil, 3, NewGroupSizes(4), 10, &Objective{Constraints: unknown}, &GreedySolver{}, rnd); err == nil {
+		t.Errorf("GenerateGroups() must fail if no member has the required role")
+	}
+}
+
+func TestViolation_String_Soft(t *testing.T) {
+	v := &Violation{Round: 1, GroupID: 2, Message: "alice and bob must be apart (bob is in group 2)", Weight: 1.5}
+	if got, want := v.String(), "round 1, group 2: alice and bob must be apart (bob is in group 2) (soft, weight 1.5)"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestGenerateGroups_SoftConstraints(t *testing.T) {
+	members := newTestMembers("alice", "bob", "carol", "dave", "ellen", "frank")
+	// the soft constraint can not be satisfied with the hard one, so it is violated without error
+	constraints := &Constraints{Pairs: []*PairConstraint{
+		{Type: Apart, Names: [2]string{"alice", "bob"}},
+		{Type: Together, Names: [2]string{"alice", "bob"}, Weight: 1},
+		{Type: Together, Names: [2]string{"carol", "dave"}, Weight: 1},
+	}}
+	rnd := rand.New(rand.NewSource(1))
+	got, err := GenerateGroups(context.Background(), members, nil, 2, NewGroupSizes(3), 10, &Objective{Constraints: constraints}, NewAnnealingSolver(10000), rnd)
+	if err != nil {
+		t.Fatalf("GenerateGroups() error = %v", err)
+	}
+	violations := constraints.Violations(got)
+	if len(violations) != 2 {
+		t.Fatalf("GenerateGroups() violations = %v, want violations of alice and bob in both rounds", violations)
+	}
+	for _, v := range violations {
+		if v.Weight == 0 || !strings.HasPrefix(v.Message, "alice and bob must be together") {
+			t.Errorf("GenerateGroups() violates an unexpected constraint: %v", v)
+		}
+	}
+}
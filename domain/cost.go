@@ -0,0 +1,207 @@
+package domain
+
+import "math"
+
+// Cost evaluates a Schedule. Solvers search a schedule which has lower cost.
+type Cost interface {
+	// Cost returns cost of the whole schedule
+	Cost(s *Schedule) float64
+	// SwapDelta returns how much the cost changes if member a and member b in round r are swapped
+	SwapDelta(s *Schedule, r, a, b int) float64
+}
+
+// boundedCost is a Cost which knows its lower bound
+type boundedCost interface {
+	Cost
+	lowerBound() float64
+}
+
+// lowerBound returns a value which cost never goes below.
+// Solvers stop when they reach it. Costs are regarded as non-negative unless they implement boundedCost.
+func lowerBound(cost Cost) float64 {
+	if b, ok := cost.(boundedCost); ok {
+		return b.lowerBound()
+	}
+	return 0
+}
+
+// rangedCost is a Cost which knows how much it can vary
+type rangedCost interface {
+	Cost
+	// roundRange returns an upper bound of difference between the highest and the lowest cost per round of memberNum members
+	roundRange(memberNum int) float64
+}
+
+// roundRange returns an upper bound of how much cost can vary per round.
+// Every Cost of Objective implements rangedCost, and other costs are regarded as constant.
+func roundRange(cost Cost, memberNum int) float64 {
+	if r, ok := cost.(rangedCost); ok {
+		return r.roundRange(memberNum)
+	}
+	return 0
+}
+
+// pairTerm is a Cost which is a sum of costs of member pairs
+type pairTerm interface {
+	Cost
+	// pairCost returns cost of member a and member b
+	pairCost(s *Schedule, a, b int) float64
+	// toggleDelta returns difference of cost of member a and member b if their meeting in round r is added or removed
+	toggleDelta(s *Schedule, r, a, b int, add bool) float64
+}
+
+// DupPairCost is a Cost which counts duplicated member pairs like CountDupMemberPairs
+type DupPairCost struct{}
+
+// Cost returns number of duplicated member pairs
+func (d DupPairCost) Cost(s *Schedule) float64 {
+	return float64(s.CountDup())
+}
+
+// SwapDelta returns difference of number of duplicated member pairs caused by swap
+func (d DupPairCost) SwapDelta(s *Schedule, r, a, b int) float64 {
+	rd := s.rounds[r]
+	ga, gb := rd.groupIndex[a], rd.groupIndex[b]
+	if ga == gb {
+		return 0
+	}
+	delta := 0
+	// member a leaves group ga and joins group gb, and vice versa
+	for _, m := range rd.groups[ga] {
+		if m != a {
+			delta += leaveDelta(s.meets[a][m]) + joinDelta(s.meets[b][m])
+		}
+	}
+	for _, m := range rd.groups[gb] {
+		if m != b {
+			delta += leaveDelta(s.meets[b][m]) + joinDelta(s.meets[a][m])
+		}
+	}
+	return float64(delta)
+}
+
+// roundRange returns number of all member pairs, because every duplicated pair meets in a round
+func (d DupPairCost) roundRange(memberNum int) float64 {
+	return float64(PairNum(memberNum))
+}
+
+func (d DupPairCost) pairCost(s *Schedule, a, b int) float64 {
+	if meets := s.meets[a][b]; meets > 1 {
+		return float64(meets - 1)
+	}
+	return 0
+}
+
+func (d DupPairCost) toggleDelta(s *Schedule, r, a, b int, add bool) float64 {
+	if add {
+		return float64(joinDelta(s.meets[a][b]))
+	}
+	return float64(leaveDelta(s.meets[a][b]))
+}
+
+func leaveDelta(meets int) int {
+	if meets > 1 {
+		return -1
+	}
+	return 0
+}
+
+func joinDelta(meets int) int {
+	if meets > 0 {
+		return 1
+	}
+	return 0
+}
+
+// Costs is a Cost which sums up costs
+type Costs []Cost
+
+// Cost returns sum of costs
+func (c Costs) Cost(s *Schedule) (total float64) {
+	for _, cost := range c {
+		total += cost.Cost(s)
+	}
+	return
+}
+
+// SwapDelta returns sum of differences of costs
+func (c Costs) SwapDelta(s *Schedule, r, a, b int) (total float64) {
+	for _, cost := range c {
+		total += cost.SwapDelta(s, r, a, b)
+	}
+	return
+}
+
+func (c Costs) lowerBound() (total float64) {
+	for _, cost := range c {
+		total += lowerBound(cost)
+	}
+	return
+}
+
+func (c Costs) roundRange(memberNum int) (total float64) {
+	for _, cost := range c {
+		total += roundRange(cost, memberNum)
+	}
+	return
+}
+
+// WeightedCost is a Cost which multiplies Term by Weight
+type WeightedCost struct {
+	Term   Cost
+	Weight float64
+}
+
+// Cost returns weighted cost
+func (w *WeightedCost) Cost(s *Schedule) float64 {
+	return w.Weight * w.Term.Cost(s)
+}
+
+// SwapDelta returns weighted difference of cost
+func (w *WeightedCost) SwapDelta(s *Schedule, r, a, b int) float64 {
+	return w.Weight * w.Term.SwapDelta(s, r, a, b)
+}
+
+func (w *WeightedCost) lowerBound() float64 {
+	switch {
+	case w.Weight == 0:
+		return 0
+	case w.Weight < 0:
+		// upper bound of Term is unknown, so the weighted cost is not bounded below
+		return math.Inf(-1)
+	}
+	return w.Weight * lowerBound(w.Term)
+}
+
+func (w *WeightedCost) roundRange(memberNum int) float64 {
+	return math.Abs(w.Weight) * roundRange(w.Term, memberNum)
+}
+
+// hardConstraintCost is a Cost which penalizes violations of hard constraints more than any change of other terms.
+// Each violation costs 1 in Term, and it is multiplied by a weight which exceeds possible range of the other terms,
+// so that solvers never trade a violation for the other terms however they are weighted.
+type hardConstraintCost struct {
+	Term Cost
+	// softRoundRange is sum of roundRange of the other terms
+	softRoundRange float64
+}
+
+// weight returns penalty of a violation in s
+func (h *hardConstraintCost) weight(s *Schedule) float64 {
+	return hardConstraintWeight(h.softRoundRange, len(s.rounds))
+}
+
+// hardConstraintWeight returns penalty of a violation of hard constraints which exceeds range of the other terms in roundNum rounds
+func hardConstraintWeight(softRoundRange float64, roundNum int) float64 {
+	return 1 + float64(roundNum)*softRoundRange
+}
+
+// Cost returns weighted number of violations
+func (h *hardConstraintCost) Cost(s *Schedule) float64 {
+	return h.weight(s) * h.Term.Cost(s)
+}
+
+// SwapDelta returns weighted difference of number of violations
+func (h *hardConstraintCost) SwapDelta(s *Schedule, r, a, b int) float64 {
+	return h.weight(s) * h.Term.SwapDelta(s, r, a, b)
+}
@@ -0,0 +1,140 @@
+package domain
+
+import "math"
+
+// Decay decides penalty of meeting a member again from number of rounds since they last met.
+// Meeting again in the next round always costs 1, as much as a duplicated member pair.
+type Decay interface {
+	// Penalty returns penalty of a repeated meeting gap rounds after the last meeting. gap is positive.
+	Penalty(gap int) float64
+}
+
+// ExponentialDecay halves penalty every HalfLife rounds
+type ExponentialDecay struct {
+	HalfLife float64
+}
+
+// Penalty returns 0.5^((gap-1)/HalfLife)
+func (e *ExponentialDecay) Penalty(gap int) float64 {
+	return math.Pow(0.5, float64(gap-1)/e.HalfLife)
+}
+
+// LinearDecay decreases penalty linearly, and meetings more than Window rounds apart are not penalized
+type LinearDecay struct {
+	Window int
+}
+
+// Penalty returns (Window-gap+1)/Window, or 0 if gap is larger than Window
+func (l *LinearDecay) Penalty(gap int) float64 {
+	if gap > l.Window {
+		return 0
+	}
+	return float64(l.Window-gap+1) / float64(l.Window)
+}
+
+// decayedDupCost is a Cost which sums up penalties of repeated meetings decided by decay
+type decayedDupCost struct {
+	decay Decay
+}
+
+// DecayedDupPenalty returns sum of penalties of all repeated meetings of member pairs in s
+func DecayedDupPenalty(s *Schedule, decay Decay) float64 {
+	return (&decayedDupCost{decay: decay}).Cost(s)
+}
+
+// Cost returns sum of penalties of repeated meetings in order of rounds
+func (d *decayedDupCost) Cost(s *Schedule) float64 {
+	// last[a][b] is 1-based round in which a and b met last, or 0 if they have never met
+	last := make([][]int, len(s.members))
+	for i := range last {
+		last[i] = make([]int, len(s.members))
+	}
+	total := 0.0
+	for r, rd := range s.rounds {
+		for _, group := range rd.groups {
+			for i, a := range group {
+				for _, b := range group[i+1:] {
+					if last[a][b] > 0 {
+						total += d.decay.Penalty(r + 1 - last[a][b])
+					}
+					last[a][b], last[b][a] = r+1, r+1
+				}
+			}
+		}
+	}
+	return total
+}
+
+// SwapDelta returns difference of penalties caused by swap.
+// Only meetings of pairs which include a or b in round r change.
+func (d *decayedDupCost) SwapDelta(s *Schedule, r, a, b int) float64 {
+	rd := s.rounds[r]
+	ga, gb := rd.groupIndex[a], rd.groupIndex[b]
+	if ga == gb {
+		return 0
+	}
+	delta := 0.0
+	// member a leaves group ga and joins group gb, and vice versa
+	for _, m := range rd.groups[ga] {
+		if m != a {
+			delta += d.toggleDelta(s, r, a, m, false) + d.toggleDelta(s, r, b, m, true)
+		}
+	}
+	for _, m := range rd.groups[gb] {
+		if m != b {
+			delta += d.toggleDelta(s, r, b, m, false) + d.toggleDelta(s, r, a, m, true)
+		}
+	}
+	return delta
+}
+
+// roundRange returns number of all member pairs, because penalty of a meeting is at most 1
+func (d *decayedDupCost) roundRange(memberNum int) float64 {
+	return float64(PairNum(memberNum))
+}
+
+// pairCost returns sum of penalties of repeated meetings of member a and member b
+func (d *decayedDupCost) pairCost(s *Schedule, a, b int) float64 {
+	total, last := 0.0, -1
+	for r, rd := range s.rounds {
+		if !rd.together(a, b) {
+			continue
+		}
+		if last != -1 {
+			total += d.decay.Penalty(r - last)
+		}
+		last = r
+	}
+	return total
+}
+
+// toggleDelta returns difference of penalties of pair a and m if their meeting in round r is added or removed
+func (d *decayedDupCost) toggleDelta(s *Schedule, r, a, m int, add bool) float64 {
+	prev, next := -1, -1
+	for p := r - 1; p >= 0; p-- {
+		if s.rounds[p].together(a, m) {
+			prev = p
+			break
+		}
+	}
+	for n := r + 1; n < len(s.rounds); n++ {
+		if s.rounds[n].together(a, m) {
+			next = n
+			break
+		}
+	}
+	delta := 0.0
+	if prev != -1 {
+		delta += d.decay.Penalty(r - prev)
+	}
+	if next != -1 {
+		delta += d.decay.Penalty(next - r)
+	}
+	if prev != -1 && next != -1 {
+		delta -= d.decay.Penalty(next - prev)
+	}
+	if !add {
+		return -delta
+	}
+	return delta
+}
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestDecay_Penalty(t *testing.T) {
+	tests := []struct {
+		name  string
+		decay Decay
+		gap   int
+		want  float64
+	}{
+		{name: "exponential next round", decay: &ExponentialDecay{HalfLife: 2}, gap: 1, want: 1},
+		{name: "exponential after half-life", decay: &ExponentialDecay{HalfLife: 2}, gap: 3, want: 0.5},
+		{name: "exponential after two half-lives", decay: &ExponentialDecay{HalfLife: 2}, gap: 5, want: 0.25},
+		{name: "linear next round", decay: &LinearDecay{Window: 4}, gap: 1, want: 1},
+		{name: "linear end of window", decay: &LinearDecay{Window: 4}, gap: 4, want: 0.25},
+		{name: "linear out of window", decay: &LinearDecay{Window: 4}, gap: 5, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.decay.Penalty(tt.gap); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Penalty() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecayedDupPenalty(t *testing.T) {
+	members := newTestMembers("alice", "bob", "carol", "dave")
+	s, err := NewScheduleFromGroupsList(members, nil)
+	if err != nil {
+		t.Fatalf("failed to create schedule: %v", err)
+	}
+	// alice and bob meet in rounds 1, 2 and 4, and carol and dave meet in rounds 1 and 4
+	for _, groups := range [][][]int{{{0, 1}, {2, 3}}, {{0, 1}, {2}, {3}}, {{0, 2}, {1, 3}}, {{0, 1}, {2, 3}}} {
+		if err := s.addRound(groups); err != nil {
+			t.Fatalf("failed to add round: %v", err)
+		}
+	}
+	tests := []struct {
+		name  string
+		decay Decay
+		want  float64
+	}{
+		{name: "exponential", decay: &ExponentialDecay{HalfLife: 1}, want: 1 + 0.5 + 0.25},
+		{name: "linear", decay: &LinearDecay{Window: 2}, want: 1 + 0.5 + 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DecayedDupPenalty(s, tt.decay); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("DecayedDupPenalty() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_decayedDupCost_SwapDelta(t *testing.T) {
+	for _, decay := range []Decay{&ExponentialDecay{HalfLife: 1.5}, &LinearDecay{Window: 3}} {
+		rnd := rand.New(rand.NewSource(1))
+		s := newTestSchedule(t, 10, 6, 3, rnd)
+		cost := &decayedDupCost{decay: decay}
+		assertSwapDelta(t, s, cost, rnd, 200)
+	}
+}
+
+func TestGenerateNextGroups_Decay(t *testing.T) {
+	// every pair has met once, so only decay decides which pairs meet again
+	members, history, err := parseGroupLinesWithMembers([][]string{
+		{"NAME", "1st", "2nd", "3rd"},
+		{"alice", "1", "1", "1"},
+		{"bob", "2", "2", "1"},
+		{"carol", "2", "1", "2"},
+		{"dave", "1", "2", "2"},
+	})
+	if err != nil {
+		t.Fatalf("failed to parse group lines: %v", err)
+	}
+	rnd := rand.New(rand.NewSource(1))
+	objective := &Objective{Decay: &ExponentialDecay{HalfLife: 1}}
+	got, err := GenerateNextGroups(context.Background(), members, history, nil, NewGroupSizes(2), 10, objective, NewAnnealingSolver(1000), rnd)
+	if err != nil {
+		t.Fatalf("GenerateNextGroups() error = %v", err)
+	}
+	if ids := got.groupIDMap(); ids["alice"] != ids["dave"] {
+		t.Errorf("GenerateNextGroups() must pair alice with dave whom she met longest ago: %v", FormatGroupLines(members, []Groups{got}))
+	}
+}
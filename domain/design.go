@@ -0,0 +1,275 @@
+package domain
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Design is a resolvable design, which is a schedule in which no pair of members meets twice
+type Design struct {
+	Name   string
+	rounds [][][]int
+}
+
+// MaxRoundNum returns number of rounds which the design has
+func (d *Design) MaxRoundNum() int {
+	return len(d.rounds)
+}
+
+// FindDesign returns a known design which divides memberNum members into groups of groupSize.
+// ok is false if no design is known for the parameters.
+func FindDesign(memberNum, groupSize int) (design *Design, ok bool) {
+	switch {
+	case groupSize < 2:
+		return nil, false
+	case groupSize == 2 && memberNum%2 == 0 && memberNum > 0:
+		return &Design{
+			Name:   fmt.Sprintf("round robin 1-factorization of %d members", memberNum),
+			rounds: roundRobinRounds(memberNum),
+		}, true
+	case memberNum == 15 && groupSize == 3:
+		return &Design{Name: "Kirkman triple system KTS(15)", rounds: kirkman15Rounds}, true
+	}
+
+	dim, n := 1, groupSize
+	for n < memberNum {
+		n *= groupSize
+		dim++
+	}
+	if n != memberNum || dim < 2 {
+		return nil, false
+	}
+	field, ok := newGaloisField(groupSize)
+	if !ok {
+		return nil, false
+	}
+	return &Design{
+		Name:   fmt.Sprintf("affine geometry AG(%d,%d)", dim, groupSize),
+		rounds: affineRounds(field, dim),
+	}, true
+}
+
+// addDesignRounds appends at most roundNum rounds of design to s.
+// Members are assigned to points of the design at random.
+func (s *Schedule) addDesignRounds(design *Design, roundNum int, rnd *rand.Rand) error {
+	perm := rnd.Perm(len(s.members))
+	for r := 0; r < roundNum && r < design.MaxRoundNum(); r++ {
+		var groups [][]int
+		for _, points := range design.rounds[r] {
+			var group []int
+			for _, point := range points {
+				group = append(group, perm[point])
+			}
+			groups = append(groups, group)
+		}
+		if err := s.addRound(groups); err != nil {
+			return fmt.Errorf("failed to add round of %s: %w", design.Name, err)
+		}
+	}
+	return nil
+}
+
+// roundRobinRounds returns rounds of pairs by the circle method.
+// Member 0 stays and the others rotate, so each member meets every other member once in memberNum-1 rounds.
+func roundRobinRounds(memberNum int) (rounds [][][]int) {
+	n := memberNum - 1
+	for r := 0; r < n; r++ {
+		groups := [][]int{{0, r + 1}}
+		for i := 1; i < memberNum/2; i++ {
+			groups = append(groups, []int{(r+i)%n + 1, (r+n-i)%n + 1})
+		}
+		rounds = append(rounds, groups)
+	}
+	return
+}
+
+// affineRounds returns parallel classes of lines of affine geometry AG(dim, q).
+// Points are vectors of GF(q)^dim and each line has q points.
+// Lines which have same direction are disjoint and cover all points, so each direction becomes a round.
+func affineRounds(field *galoisField, dim int) (rounds [][][]int) {
+	q := field.q
+	pointNum := 1
+	for i := 0; i < dim; i++ {
+		pointNum *= q
+	}
+	toVector := func(p int) []int {
+		v := make([]int, dim)
+		for i := range v {
+			v[i] = p % q
+			p /= q
+		}
+		return v
+	}
+	toPoint := func(v []int) (p int) {
+		for i := dim - 1; i >= 0; i-- {
+			p = p*q + v[i]
+		}
+		return
+	}
+
+	for d := 1; d < pointNum; d++ {
+		direction := toVector(d)
+		// directions are normalized so that the last non-zero coordinate is 1
+		if lastNonZero(direction) != 1 {
+			continue
+		}
+
+		visited := make([]bool, pointNum)
+		var groups [][]int
+		for p := 0; p < pointNum; p++ {
+			if visited[p] {
+				continue
+			}
+			base := toVector(p)
+			var line []int
+			for t := 0; t < q; t++ {
+				v := make([]int, dim)
+				for i := range v {
+					v[i] = field.add[base[i]][field.mul[t][direction[i]]]
+				}
+				point := toPoint(v)
+				visited[point] = true
+				line = append(line, point)
+			}
+			groups = append(groups, line)
+		}
+		rounds = append(rounds, groups)
+	}
+	return
+}
+
+func lastNonZero(v []int) int {
+	for i := len(v) - 1; i >= 0; i-- {
+		if v[i] != 0 {
+			return v[i]
+		}
+	}
+	return 0
+}
+
+// galoisField is a finite field GF(q) whose elements are 0...q-1.
+// An element represents a polynomial over GF(p) whose coefficients are digits of the element in base p.
+type galoisField struct {
+	q   int
+	add [][]int
+	mul [][]int
+}
+
+// newGaloisField returns GF(q). ok is false if q is not a prime power.
+func newGaloisField(q int) (field *galoisField, ok bool) {
+	p := smallestPrimeFactor(q)
+	if p == 0 {
+		return nil, false
+	}
+	degree, n := 0, 1
+	for n < q {
+		n *= p
+		degree++
+	}
+	if n != q {
+		return nil, false
+	}
+
+	add := newTable(q)
+	for a := 0; a < q; a++ {
+		for b := 0; b < q; b++ {
+			add[a][b] = polyAdd(a, b, p)
+		}
+	}
+	// try monic polynomials of the degree until multiplication modulo the polynomial makes a field
+	for modulus := q; modulus < 2*q; modulus++ {
+		mul := newTable(q)
+		for a := 0; a < q; a++ {
+			for b := 0; b < q; b++ {
+				mul[a][b] = polyMulMod(a, b, modulus, p, degree)
+			}
+		}
+		if hasInverses(mul) {
+			return &galoisField{q: q, add: add, mul: mul}, true
+		}
+	}
+	return nil, false
+}
+
+func newTable(q int) [][]int {
+	table := make([][]int, q)
+	for i := range table {
+		table[i] = make([]int, q)
+	}
+	return table
+}
+
+func hasInverses(mul [][]int) bool {
+	for a := 1; a < len(mul); a++ {
+		found := false
+		for b := 1; b < len(mul); b++ {
+			if mul[a][b] == 1 {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func smallestPrimeFactor(n int) int {
+	for p := 2; p <= n; p++ {
+		if n%p == 0 {
+			return p
+		}
+	}
+	return 0
+}
+
+func polyAdd(a, b, p int) (c int) {
+	for digit := 1; a > 0 || b > 0; digit *= p {
+		c += (a%p + b%p) % p * digit
+		a, b = a/p, b/p
+	}
+	return
+}
+
+// polyMulMod multiplies polynomials a and b modulo the monic polynomial modulus of the degree
+func polyMulMod(a, b, modulus, p, degree int) int {
+	coefficients := func(x, n int) []int {
+		c := make([]int, n)
+		for i := range c {
+			c[i] = x % p
+			x /= p
+		}
+		return c
+	}
+	ca, cb, cm := coefficients(a, degree), coefficients(b, degree), coefficients(modulus, degree+1)
+	product := make([]int, 2*degree)
+	for i, x := range ca {
+		for j, y := range cb {
+			product[i+j] = (product[i+j] + x*y) % p
+		}
+	}
+	// reduce terms of degree or higher by subtracting multiples of the modulus
+	for i := len(product) - 1; i >= degree; i-- {
+		factor := product[i]
+		for j := 0; j <= degree; j++ {
+			product[i-degree+j] = ((product[i-degree+j]-factor*cm[j])%p + p) % p
+		}
+	}
+	c := 0
+	for i := degree - 1; i >= 0; i-- {
+		c = c*p + product[i]
+	}
+	return c
+}
+
+// kirkman15Rounds is a solution of Kirkman's schoolgirl problem, in which 15 members walk in groups of 3 for 7 rounds
+var kirkman15Rounds = [][][]int{
+	{{0, 5, 9}, {1, 4, 7}, {2, 11, 14}, {3, 8, 10}, {6, 12, 13}},
+	{{0, 7, 12}, {1, 2, 8}, {3, 4, 5}, {6, 9, 14}, {10, 11, 13}},
+	{{0, 1, 11}, {2, 9, 10}, {3, 7, 13}, {4, 6, 8}, {5, 12, 14}},
+	{{0, 2, 4}, {1, 13, 14}, {3, 6, 11}, {5, 7, 10}, {8, 9, 12}},
+	{{0, 6, 10}, {1, 3, 9}, {2, 5, 13}, {4, 11, 12}, {7, 8, 14}},
+	{{0, 3, 14}, {1, 10, 12}, {2, 6, 7}, {4, 9, 13}, {5, 8, 11}},
+	{{0, 8, 13}, {1, 5, 6}, {2, 3, 12}, {4, 10, 14}, {7, 9, 11}},
+}
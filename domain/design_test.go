@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestFindDesign(t *testing.T) {
+	tests := []struct {
+		memberNum    int
+		groupSize    int
+		wantOK       bool
+		wantRoundNum int
+	}{
+		{memberNum: 8, groupSize: 2, wantOK: true, wantRoundNum: 7},
+		{memberNum: 9, groupSize: 3, wantOK: true, wantRoundNum: 4},
+		{memberNum: 15, groupSize: 3, wantOK: true, wantRoundNum: 7},
+		{memberNum: 16, groupSize: 4, wantOK: true, wantRoundNum: 5},
+		{memberNum: 25, groupSize: 5, wantOK: true, wantRoundNum: 6},
+		{memberNum: 27, groupSize: 3, wantOK: true, wantRoundNum: 13},
+		{memberNum: 64, groupSize: 8, wantOK: true, wantRoundNum: 9},
+		{memberNum: 81, groupSize: 9, wantOK: true, wantRoundNum: 10},
+		{memberNum: 7, groupSize: 2, wantOK: false},
+		{memberNum: 36, groupSize: 6, wantOK: false},
+		{memberNum: 12, groupSize: 4, wantOK: false},
+		{memberNum: 4, groupSize: 4, wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d members in groups of %d", tt.memberNum, tt.groupSize), func(t *testing.T) {
+			design, ok := FindDesign(tt.memberNum, tt.groupSize)
+			if ok != tt.wantOK {
+				t.Fatalf("FindDesign() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if design.MaxRoundNum() != tt.wantRoundNum {
+				t.Errorf("FindDesign() round num = %v, want %v", design.MaxRoundNum(), tt.wantRoundNum)
+			}
+
+			var names []string
+			for i := 0; i < tt.memberNum; i++ {
+				names = append(names, fmt.Sprintf("member%d", i))
+			}
+			s := newSchedule(newTestMembers(names...))
+			for r, groups := range design.rounds {
+				for _, group := range groups {
+					if len(group) != tt.groupSize {
+						t.Errorf("round %d has group of size %d", r, len(group))
+					}
+				}
+				if err := s.addRound(groups); err != nil {
+					t.Fatalf("invalid round: %v", err)
+				}
+			}
+			if s.CountDup() != 0 {
+				t.Errorf("%s has %d duplicated pairs", design.Name, s.CountDup())
+			}
+		})
+	}
+}
+
+func TestDesignRounds(t *testing.T) {
+	var names []string
+	for i := 0; i < 16; i++ {
+		names = append(names, fmt.Sprintf("member%d", i))
+	}
+	members := newTestMembers(names...)
+	pins := &Objective{Constraints: &Constraints{Pins: []*Pin{{Name: "member0", Round: 3, GroupID: 1}}}}
+	tests := []struct {
+		name         string
+		roundNum     int
+		groupSize    int
+		availability *Availability
+		objective    *Objective
+		want         int
+	}{
+		{name: "rounds fewer than design", roundNum: 3, groupSize: 4, want: 3},
+		{name: "rounds more than design", roundNum: 8, groupSize: 4, want: 5},
+		{name: "absent member", roundNum: 8, groupSize: 4, availability: newAvailability([][]string{nil, {"member3"}}), want: 1},
+		{name: "pinned member", roundNum: 8, groupSize: 4, objective: pins, want: 2},
+		{name: "no design", roundNum: 8, groupSize: 3, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, got, err := DesignRounds(members, tt.availability, tt.roundNum, NewGroupSizes(tt.groupSize), tt.objective)
+			if err != nil {
+				t.Fatalf("DesignRounds() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("DesignRounds() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
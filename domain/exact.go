@@ -0,0 +1,321 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// MaxExactMemberNum is the maximum number of members which SolveExact accepts
+const MaxExactMemberNum = 16
+
+// ExactResult is a result of SolveExact.
+// It works as a certificate of optimality when Dup equals LowerBound.
+type ExactResult struct {
+	Schedule *Schedule
+	// Dup is number of duplicated member pairs of Schedule
+	Dup int
+	// LowerBound is a proven lower bound of number of duplicated member pairs of any schedule
+	LowerBound int
+	// CountingBound is the lower bound derived from number of pairs each member can meet
+	CountingBound int
+	// Nodes is number of search nodes explored
+	Nodes int
+	// Complete is true if the search space was explored exhaustively
+	Complete bool
+}
+
+// Optimal returns true if it is proven that no schedule has fewer duplicated member pairs than Schedule
+func (e *ExactResult) Optimal() bool {
+	return e.Dup == e.LowerBound
+}
+
+// Proof describes why LowerBound holds
+func (e *ExactResult) Proof() string {
+	switch {
+	case e.Dup == e.CountingBound:
+		return "every member meets at least as many members as possible, so the counting bound is attained"
+	case e.Complete:
+		return fmt.Sprintf("exhaustive branch and bound search over %d nodes found no better schedule", e.Nodes)
+	default:
+		return fmt.Sprintf("search stopped after %d nodes, so only the counting bound is proven", e.Nodes)
+	}
+}
+
+// SolveExact searches a schedule which has minimum number of duplicated member pairs by branch and bound.
+// capacitiesList has group capacities of each round. initial is used as the first upper bound and may be nil.
+// The search stops after maxNodes nodes or when ctx is done, and then the best schedule found so far is returned with Complete false.
+func SolveExact(ctx context.Context, members []*Member, capacitiesList [][]int, initial *Schedule, maxNodes int) (*ExactResult, error) {
+	if len(members) > MaxExactMemberNum {
+		return nil, fmt.Errorf("too many members for exact solver. max: %d, actual: %d", MaxExactMemberNum, len(members))
+	}
+	if len(capacitiesList) == 0 {
+		return nil, fmt.Errorf("number of rounds must be positive")
+	}
+	for _, capacities := range capacitiesList {
+		if sum(capacities) != len(members) {
+			return nil, fmt.Errorf("total group capacity(%d) is not equal to number of members(%d)", sum(capacities), len(members))
+		}
+	}
+
+	e := newExactSearch(ctx, members, capacitiesList, maxNodes)
+	if initial != nil {
+		e.best, e.bestDup = initial.clone(), initial.CountDup()
+	}
+	countingBound := DupLowerBound(e.capacitiesList)
+	if e.best == nil || e.bestDup > countingBound {
+		e.placeFirstRound()
+		e.search(1)
+	}
+	if e.best == nil {
+		return nil, fmt.Errorf("no schedule is found within %d nodes", maxNodes)
+	}
+
+	result := &ExactResult{
+		Schedule:      e.best,
+		Dup:           e.bestDup,
+		LowerBound:    countingBound,
+		CountingBound: countingBound,
+		Nodes:         e.nodes,
+		Complete:      !e.aborted,
+	}
+	if result.Complete {
+		result.LowerBound = result.Dup
+	}
+	return result, nil
+}
+
+type exactSearch struct {
+	ctx            context.Context
+	s              *Schedule
+	capacitiesList [][]int
+	// sameShape is true if every round has same group capacities, so rounds are interchangeable
+	sameShape bool
+	maxNodes  int
+	nodes     int
+	aborted   bool
+	dup       int
+	best      *Schedule
+	bestDup   int
+	// order has members of each round in order of placement
+	order [][]int
+}
+
+func newExactSearch(ctx context.Context, members []*Member, capacitiesList [][]int, maxNodes int) *exactSearch {
+	sorted := make([][]int, len(capacitiesList))
+	sameShape := true
+	for r, capacities := range capacitiesList {
+		sorted[r] = append([]int{}, capacities...)
+		sort.Sort(sort.Reverse(sort.IntSlice(sorted[r])))
+		if fmt.Sprint(sorted[r]) != fmt.Sprint(sorted[0]) {
+			sameShape = false
+		}
+	}
+	return &exactSearch{
+		ctx:            ctx,
+		s:              newSchedule(members),
+		capacitiesList: sorted,
+		sameShape:      sameShape,
+		maxNodes:       maxNodes,
+		order:          make([][]int, len(capacitiesList)),
+	}
+}
+
+// placeFirstRound fixes the first round without loss of generality because members can be renamed
+func (e *exactSearch) placeFirstRound() {
+	var groups [][]int
+	m := 0
+	for _, capacity := range e.capacitiesList[0] {
+		var group []int
+		for i := 0; i < capacity; i++ {
+			group = append(group, m)
+			e.order[0] = append(e.order[0], m)
+			m++
+		}
+		groups = append(groups, group)
+	}
+	_ = e.s.addRound(groups)
+}
+
+// lowerBound returns lower bound of number of duplicated member pairs which rounds from r introduce
+func (e *exactSearch) lowerBound(r int) int {
+	remaining := 0
+	for _, capacities := range e.capacitiesList[r:] {
+		remaining += capacities[len(capacities)-1] - 1
+	}
+	total := 0
+	for i := range e.s.members {
+		unmet := 0
+		for j := range e.s.members {
+			if i != j && e.s.meets[i][j] == 0 {
+				unmet++
+			}
+		}
+		if need := remaining - unmet; need > 0 {
+			total += need
+		}
+	}
+	// each duplicated pair is counted from both members
+	return (total + 1) / 2
+}
+
+func (e *exactSearch) search(r int) {
+	if r == len(e.capacitiesList) {
+		if e.best == nil || e.dup < e.bestDup {
+			e.best, e.bestDup = e.s.clone(), e.dup
+		}
+		return
+	}
+	if e.best != nil && e.dup+e.lowerBound(r) >= e.bestDup {
+		return
+	}
+
+	capacities := e.capacitiesList[r]
+	e.s.rounds = append(e.s.rounds, &round{groupIndex: make([]int, len(e.s.members))})
+	for i := range e.s.rounds[r].groupIndex {
+		e.s.rounds[r].groupIndex[i] = -1
+	}
+	remaining := map[int]int{}
+	for _, capacity := range capacities {
+		remaining[capacity]++
+	}
+	e.startGroup(r, remaining, true)
+	e.s.rounds = e.s.rounds[:r]
+}
+
+// startGroup opens a new group which begins with the smallest unassigned member.
+// Groups which have same capacity are interchangeable, so it is enough to decide capacity of the group.
+func (e *exactSearch) startGroup(r int, remaining map[int]int, sameAsPrev bool) {
+	rd := e.s.rounds[r]
+	first := -1
+	for m, g := range rd.groupIndex {
+		if g == -1 {
+			first = m
+			break
+		}
+	}
+	if first == -1 {
+		e.search(r + 1)
+		return
+	}
+
+	var capacities []int
+	for capacity, n := range remaining {
+		if n > 0 {
+			capacities = append(capacities, capacity)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(capacities)))
+	for _, capacity := range capacities {
+		remaining[capacity]--
+		rd.groups = append(rd.groups, nil)
+		e.add(r, first, sameAsPrev, func(sameAsPrev bool) {
+			e.fillGroup(r, capacity, remaining, sameAsPrev)
+		})
+		rd.groups = rd.groups[:len(rd.groups)-1]
+		remaining[capacity]++
+	}
+}
+
+// fillGroup adds members to the last group of round r in ascending order until it has capacity members
+func (e *exactSearch) fillGroup(r, capacity int, remaining map[int]int, sameAsPrev bool) {
+	rd := e.s.rounds[r]
+	g := len(rd.groups) - 1
+	group := rd.groups[g]
+	if len(group) == capacity {
+		e.startGroup(r, remaining, sameAsPrev)
+		return
+	}
+	for m := group[len(group)-1] + 1; m < len(e.s.members); m++ {
+		if rd.groupIndex[m] != -1 {
+			continue
+		}
+		e.add(r, m, sameAsPrev, func(sameAsPrev bool) {
+			e.fillGroup(r, capacity, remaining, sameAsPrev)
+		})
+	}
+}
+
+// add places member m to the last group of round r and calls next, then removes m again
+func (e *exactSearch) add(r, m int, sameAsPrev bool, next func(sameAsPrev bool)) {
+	if e.nodes >= e.maxNodes || (e.nodes%ctxCheckInterval == 0 && e.ctx.Err() != nil) {
+		e.aborted = true
+		return
+	}
+	// rounds are interchangeable if all rounds have same shape,
+	// so only rounds which are placed in lexicographic order are searched.
+	pos := len(e.order[r])
+	if e.sameShape && sameAsPrev {
+		prev := e.order[r-1][pos]
+		if m < prev {
+			return
+		}
+		sameAsPrev = m == prev
+	}
+
+	rd := e.s.rounds[r]
+	g := len(rd.groups) - 1
+	delta := 0
+	for _, other := range rd.groups[g] {
+		if e.s.meets[m][other] > 0 {
+			delta++
+		}
+		e.s.meets[m][other]++
+		e.s.meets[other][m]++
+	}
+	e.nodes++
+	e.dup += delta
+	rd.groups[g] = append(rd.groups[g], m)
+	rd.groupIndex[m] = g
+	e.order[r] = append(e.order[r], m)
+
+	if e.best == nil || e.dup < e.bestDup {
+		next(sameAsPrev)
+	}
+
+	e.order[r] = e.order[r][:pos]
+	rd.groupIndex[m] = -1
+	rd.groups[g] = rd.groups[g][:len(rd.groups[g])-1]
+	e.dup -= delta
+	for _, other := range rd.groups[g] {
+		e.s.meets[m][other]--
+		e.s.meets[other][m]--
+	}
+}
+
+// warmStartShare is the share of remaining time until the deadline which the solver of FindOptimalSchedule may use
+const warmStartShare = 0.5
+
+// FindOptimalSchedule improves initial by solver and then proves optimality of the result by SolveExact.
+// Group capacities of each round of initial are kept. initial must not have fixed rounds.
+// If ctx has a deadline, solver is stopped after warmStartShare of the remaining time so that SolveExact has the rest.
+func FindOptimalSchedule(ctx context.Context, initial *Schedule, solver Solver, maxNodes int, rnd *rand.Rand) (*ExactResult, error) {
+	if initial.fixedRoundNum > 0 {
+		return nil, fmt.Errorf("exact solver does not support fixed rounds")
+	}
+	for r, rd := range initial.rounds {
+		if rd.pinned != nil {
+			return nil, fmt.Errorf("exact solver does not support pinned members: round %d has pinned members", r+1)
+		}
+	}
+	for r, capacities := range initial.CapacitiesList() {
+		if sum(capacities) != len(initial.members) {
+			return nil, fmt.Errorf("exact solver does not support absent members: %d of %d members attend round %d", sum(capacities), len(initial.members), r+1)
+		}
+	}
+	warmCtx, cancel := warmStartContext(ctx)
+	defer cancel()
+	warmStart := solver.Solve(warmCtx, initial.clone(), DupPairCost{}, rnd)
+	return SolveExact(ctx, initial.members, initial.CapacitiesList(), warmStart, maxNodes)
+}
+
+// warmStartContext returns context which is done after warmStartShare of the remaining time of ctx passes
+func warmStartContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, time.Duration(float64(time.Until(deadline))*warmStartShare))
+}
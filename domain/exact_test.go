@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestSolveExact(t *testing.T) {
+	tests := []struct {
+		name         string
+		memberNum    int
+		groupSize    int
+		roundNum     int
+		maxNodes     int
+		wantDup      int
+		wantOptimal  bool
+		wantComplete bool
+	}{
+		{name: "perfect schedule", memberNum: 4, groupSize: 2, roundNum: 3, maxNodes: 1000, wantDup: 0, wantOptimal: true, wantComplete: true},
+		{name: "counting bound is attained", memberNum: 8, groupSize: 4, roundNum: 3, maxNodes: 100000, wantDup: 8, wantOptimal: true, wantComplete: true},
+		{name: "counting bound is not attained", memberNum: 6, groupSize: 3, roundNum: 3, maxNodes: 100000, wantDup: 5, wantOptimal: true, wantComplete: true},
+		{name: "search is stopped", memberNum: 6, groupSize: 3, roundNum: 3, maxNodes: 20, wantDup: 8, wantOptimal: false, wantComplete: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var names []string
+			for i := 0; i < tt.memberNum; i++ {
+				names = append(names, fmt.Sprintf("member%d", i))
+			}
+			capacities, err := GroupCapacities(tt.memberNum, tt.groupSize)
+			if err != nil {
+				t.Fatalf("failed to decide capacities: %v", err)
+			}
+			var capacitiesList [][]int
+			for i := 0; i < tt.roundNum; i++ {
+				capacitiesList = append(capacitiesList, capacities)
+			}
+
+			got, err := SolveExact(context.Background(), newTestMembers(names...), capacitiesList, nil, tt.maxNodes)
+			if err != nil {
+				t.Fatalf("SolveExact() error = %v", err)
+			}
+			if got.Dup != tt.wantDup || got.Schedule.CountDup() != tt.wantDup {
+				t.Errorf("SolveExact() dup = %v, schedule dup = %v, want %v", got.Dup, got.Schedule.CountDup(), tt.wantDup)
+			}
+			if got.Optimal() != tt.wantOptimal {
+				t.Errorf("SolveExact() optimal = %v, want %v", got.Optimal(), tt.wantOptimal)
+			}
+			if got.Complete != tt.wantComplete {
+				t.Errorf("SolveExact() complete = %v, want %v", got.Complete, tt.wantComplete)
+			}
+		})
+	}
+}
+
+func TestFindOptimalSchedule_TimeLimit(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	s := newTestSchedule(t, 6, 3, 3, rnd)
+	// unlimited starts keep solving until warm start context is done
+	solver := NewMultiStartSolver(NewAnnealingSolver(1000), -1, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	got, err := FindOptimalSchedule(ctx, s, solver, 100000, rnd)
+	if err != nil {
+		t.Fatalf("FindOptimalSchedule() error = %v", err)
+	}
+	if got.Nodes == 0 {
+		t.Errorf("FindOptimalSchedule() must leave time for exact search: %s", got.Proof())
+	}
+	if !got.Optimal() || got.Dup != 5 {
+		t.Errorf("FindOptimalSchedule() dup = %v, optimal = %v, want 5 and optimal", got.Dup, got.Optimal())
+	}
+}
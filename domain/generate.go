@@ -0,0 +1,178 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// GenerateGroups generates groupsList of roundNum rounds which has as few duplicated member pairs as possible.
+// Rounds are taken from a known design if exists, and the rest are built greedily.
+// Members who are absent from a round according to availability are not assigned to groups of the round.
+// Members pinned by constraints of objective are assigned to their groups, and solver never moves them.
+// The best schedule of trials attempts is improved by solver to minimize objective.
+// An error is returned if the result violates constraints of objective.
+func GenerateGroups(ctx context.Context, members []*Member, availability *Availability, roundNum int, sizes *GroupSizes, trials int, objective *Objective, solver Solver, rnd *rand.Rand) ([]Groups, error) {
+	if roundNum < 1 {
+		return nil, fmt.Errorf("number of rounds must be positive: %d", roundNum)
+	}
+	if err := availability.validate(members); err != nil {
+		return nil, err
+	}
+	cost, err := objective.newCost(members)
+	if err != nil {
+		return nil, err
+	}
+	pinned, err := objective.pinnedGroups(members)
+	if err != nil {
+		return nil, err
+	}
+	for r := range pinned {
+		if r >= roundNum {
+			return nil, fmt.Errorf("members are pinned in round %d, but number of rounds is %d", r+1, roundNum)
+		}
+	}
+	s, err := newInitialSchedule(members, availability, pinned, roundNum, sizes, trials, cost, rnd)
+	if err != nil {
+		return nil, err
+	}
+	s = solver.Solve(ctx, s, cost, rnd)
+	if err := objective.check(s); err != nil {
+		return nil, err
+	}
+	return s.GroupsList(), nil
+}
+
+// GenerateOptimalGroups generates groupsList of roundNum rounds which has minimum number of duplicated member pairs.
+// Groups improved by solver are used as the initial upper bound of SolveExact.
+func GenerateOptimalGroups(ctx context.Context, members []*Member, roundNum int, sizes *GroupSizes, trials int, solver Solver, maxNodes int, rnd *rand.Rand) (*ExactResult, error) {
+	if roundNum < 1 {
+		return nil, fmt.Errorf("number of rounds must be positive: %d", roundNum)
+	}
+	s, err := newInitialSchedule(members, nil, nil, roundNum, sizes, trials, DupPairCost{}, rnd)
+	if err != nil {
+		return nil, err
+	}
+	return FindOptimalSchedule(ctx, s, solver, maxNodes, rnd)
+}
+
+// GenerateNextGroups generates groups of the round which follows history.
+// The round is built greedily and improved by solver to minimize objective, so that it introduces as few duplicated member pairs as possible.
+// Violations of constraints in history are ignored. Members can be pinned only to the new round,
+// and round index of the new round in availability and pins is len(history).
+func GenerateNextGroups(ctx context.Context, members []*Member, history []Groups, availability *Availability, sizes *GroupSizes, trials int, objective *Objective, solver Solver, rnd *rand.Rand) (Groups, error) {
+	s, err := NewScheduleFromGroupsList(members, history)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load history: %w", err)
+	}
+	if err := availability.validate(members); err != nil {
+		return nil, err
+	}
+	cost, err := objective.newCost(members)
+	if err != nil {
+		return nil, err
+	}
+	pinned, err := objective.pinnedGroups(members)
+	if err != nil {
+		return nil, err
+	}
+	for r := range pinned {
+		if r != len(history) {
+			return nil, fmt.Errorf("members are pinned in round %d, but only round %d is generated", r+1, len(history)+1)
+		}
+	}
+	s.FixRounds(len(history))
+	s, err = generateGreedySchedule(s, availability, pinned, 1, sizes, trials, cost, rnd)
+	if err != nil {
+		return nil, err
+	}
+	s = solver.Solve(ctx, s, cost, rnd)
+	if err := objective.check(s); err != nil {
+		return nil, err
+	}
+	groupsList := s.GroupsList()
+	return groupsList[len(groupsList)-1], nil
+}
+
+// newInitialSchedule generates schedule of roundNum rounds from rounds of a known design and greedy rounds.
+// The design is used only for leading rounds which every member attends and which have no pinned members.
+func newInitialSchedule(members []*Member, availability *Availability, pinned map[int]map[int]int, roundNum int, sizes *GroupSizes, trials int, cost Cost, rnd *rand.Rand) (*Schedule, error) {
+	s, err := NewScheduleFromGroupsList(members, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid members: %w", err)
+	}
+	if design, designRoundNum := designRounds(members, availability, pinned, roundNum, sizes); designRoundNum > 0 {
+		if err := s.addDesignRounds(design, designRoundNum, rnd); err != nil {
+			return nil, err
+		}
+	}
+	return generateGreedySchedule(s, availability, pinned, roundNum-s.RoundNum(), sizes, trials, cost, rnd)
+}
+
+// DesignRounds returns a known design which GenerateGroups uses and number of leading rounds built from it.
+// The number is 0 if no design is found, or if the first round is not fully attended or has pinned members.
+func DesignRounds(members []*Member, availability *Availability, roundNum int, sizes *GroupSizes, objective *Objective) (*Design, int, error) {
+	pinned, err := objective.pinnedGroups(members)
+	if err != nil {
+		return nil, 0, err
+	}
+	design, n := designRounds(members, availability, pinned, roundNum, sizes)
+	return design, n, nil
+}
+
+// designRounds returns a known design and number of leading rounds which every member attends and which have no pinned members
+func designRounds(members []*Member, availability *Availability, pinned map[int]map[int]int, roundNum int, sizes *GroupSizes) (*Design, int) {
+	design, ok := sizes.Design(len(members))
+	if !ok {
+		return nil, 0
+	}
+	n := availability.fullRoundNum(members, 0, roundNum)
+	if maxRoundNum := design.MaxRoundNum(); maxRoundNum < n {
+		n = maxRoundNum
+	}
+	for r := range pinned {
+		if r < n {
+			n = r
+		}
+	}
+	return design, n
+}
+
+// generateGreedySchedule appends roundNum greedy rounds to s, and returns the schedule which has the lowest cost in trials attempts.
+// pinned has groups of pinned members keyed by 0-based round.
+func generateGreedySchedule(s *Schedule, availability *Availability, pinned map[int]map[int]int, roundNum int, sizes *GroupSizes, trials int, cost Cost, rnd *rand.Rand) (*Schedule, error) {
+	capacitiesList := make([][]int, roundNum)
+	attendeesList := make([][]int, roundNum)
+	for r := range capacitiesList {
+		attendeesList[r] = availability.attendees(s.members, s.RoundNum()+r)
+		capacities, err := sizes.Capacities(len(attendeesList[r]), s.RoundNum()+r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decide group capacities of round %d: %w", s.RoundNum()+r+1, err)
+		}
+		capacitiesList[r] = capacities
+	}
+
+	// cost of s is the lowest possible if cost is non-negative, because rounds never decrease such cost
+	lowest := cost.Cost(s)
+	if lowerBound(cost) < 0 {
+		lowest = math.Inf(-1)
+	}
+	var best *Schedule
+	var bestCost float64
+	for i := 0; i < trials || best == nil; i++ {
+		c := s.clone()
+		for r := 0; r < roundNum; r++ {
+			if err := c.addGreedyRound(capacitiesList[r], attendeesList[r], pinned[c.RoundNum()], rnd); err != nil {
+				return nil, fmt.Errorf("failed to generate round %d: %w", c.RoundNum()+1, err)
+			}
+		}
+		if cc := cost.Cost(c); best == nil || cc < bestCost {
+			best, bestCost = c, cc
+		}
+		if bestCost == lowest {
+			break
+		}
+	}
+	return best, nil
+}
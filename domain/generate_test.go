@@ -0,0 +1,119 @@
+package domain
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+)
+
+func newTestMembers(names ...string) (members []*Member) {
+	for i, name := range names {
+		members = append(members, &Member{ID: MemberID(i + 1), Name: name})
+	}
+	return
+}
+
+func TestGenerateGroups(t *testing.T) {
+	type args struct {
+		members   []*Member
+		roundNum  int
+		groupSize int
+	}
+	tests := []struct {
+		name         string
+		args         args
+		wantGroupNum int
+		wantDup      int
+		wantErr      bool
+	}{
+		{
+			name: "4 members can meet different members in 3 rounds",
+			args: args{
+				members:   newTestMembers("alice", "bob", "carol", "dave"),
+				roundNum:  3,
+				groupSize: 2,
+			},
+			wantGroupNum: 2,
+			wantDup:      0,
+		},
+		{
+			name: "uneven groups",
+			args: args{
+				members:   newTestMembers("alice", "bob", "carol", "dave", "ellen"),
+				roundNum:  1,
+				groupSize: 2,
+			},
+			wantGroupNum: 2,
+			wantDup:      0,
+		},
+		{
+			name: "group size is larger than number of members",
+			args: args{
+				members:   newTestMembers("alice", "bob"),
+				roundNum:  1,
+				groupSize: 3,
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicated member names",
+			args: args{
+				members:   newTestMembers("alice", "alice", "bob", "carol"),
+				roundNum:  1,
+				groupSize: 2,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateGroups(context.Background(), tt.args.members, nil, tt.args.roundNum, NewGroupSizes(tt.args.groupSize), 100, nil, &GreedySolver{}, rand.New(rand.NewSource(1)))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GenerateGroups() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got) != tt.args.roundNum {
+				t.Errorf("GenerateGroups() round num = %v, want %v", len(got), tt.args.roundNum)
+			}
+			for _, groups := range got {
+				if len(groups) != tt.wantGroupNum {
+					t.Errorf("GenerateGroups() group num = %v, want %v", len(groups), tt.wantGroupNum)
+				}
+				memberNum := 0
+				for _, group := range groups {
+					memberNum += len(group.members)
+				}
+				if memberNum != len(tt.args.members) {
+					t.Errorf("GenerateGroups() member num = %v, want %v", memberNum, len(tt.args.members))
+				}
+			}
+			if dup, _ := CountDupMemberPairs(got); dup != tt.wantDup {
+				t.Errorf("GenerateGroups() dup = %v, want %v", dup, tt.wantDup)
+			}
+		})
+	}
+}
+
+func TestGenerateNextGroups(t *testing.T) {
+	members, history, err := parseGroupLinesWithMembers([][]string{
+		{"NAME", "1st", "2nd"},
+		{"alice", "1", "1"},
+		{"bob", "1", "2"},
+		{"carol", "2", "1"},
+		{"dave", "2", "2"},
+	})
+	if err != nil {
+		t.Fatalf("failed to parse group lines: %v", err)
+	}
+
+	got, err := GenerateNextGroups(context.Background(), members, history, nil, NewGroupSizes(2), 100, nil, &GreedySolver{}, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatalf("GenerateNextGroups() error = %v", err)
+	}
+	if dup, _ := CountDupMemberPairs(append(history, got)); dup != 0 {
+		t.Errorf("GenerateNextGroups() dup = %v, want %v", dup, 0)
+	}
+}
@@ -0,0 +1,186 @@
+package domain
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"sync/atomic"
+)
+
+// GeneticSolver is a Solver which uses a genetic algorithm whose genes are rounds.
+// A child takes each round from either parent, or mixes groups of both parents in the round and repairs group sizes.
+// Children are mutated by swapping members, and the best schedule always survives to the next generation.
+type GeneticSolver struct {
+	// count is accessed atomically, so it is placed first to be 64-bit aligned
+	count       int64
+	Population  int
+	Generations int
+	// MutationRate is probability that a child is mutated
+	MutationRate float64
+}
+
+// NewGeneticSolver generates GeneticSolver which has default mutation rate
+func NewGeneticSolver(population, generations int) *GeneticSolver {
+	return &GeneticSolver{
+		Population:   population,
+		Generations:  generations,
+		MutationRate: 0.3,
+	}
+}
+
+type individual struct {
+	s    *Schedule
+	cost float64
+}
+
+// Solve evolves population from s and returns the best schedule found
+func (g *GeneticSolver) Solve(ctx context.Context, s *Schedule, cost Cost, rnd *rand.Rand) *Schedule {
+	population := []*individual{{s: s.clone(), cost: cost.Cost(s)}}
+	if len(s.rounds) <= s.fixedRoundNum || g.Population < 2 {
+		return population[0].s
+	}
+	for len(population) < g.Population {
+		c := s.clone()
+		c.shuffle(rnd)
+		population = append(population, &individual{s: c, cost: cost.Cost(c)})
+	}
+	sortIndividuals(population)
+
+	i := 0
+	defer func() {
+		atomic.AddInt64(&g.count, int64(i))
+	}()
+	for bound := lowerBound(cost); i < g.Generations && population[0].cost > bound && ctx.Err() == nil; i++ {
+		next := []*individual{population[0]}
+		for len(next) < g.Population {
+			child := g.crossover(g.selectParent(population, rnd).s, g.selectParent(population, rnd).s, rnd)
+			if rnd.Float64() < g.MutationRate {
+				mutate(child, cost, rnd)
+			}
+			next = append(next, &individual{s: child, cost: cost.Cost(child)})
+		}
+		sortIndividuals(next)
+		population = next
+	}
+	return population[0].s
+}
+
+// IterationCount returns total number of generations of all Solve calls
+func (g *GeneticSolver) IterationCount() int64 {
+	return atomic.LoadInt64(&g.count)
+}
+
+// sortIndividuals sorts population in ascending order of cost
+func sortIndividuals(population []*individual) {
+	sort.SliceStable(population, func(i, j int) bool {
+		return population[i].cost < population[j].cost
+	})
+}
+
+// selectParent returns the better of two random individuals
+func (g *GeneticSolver) selectParent(population []*individual, rnd *rand.Rand) *individual {
+	a, b := population[rnd.Intn(len(population))], population[rnd.Intn(len(population))]
+	if b.cost < a.cost {
+		return b
+	}
+	return a
+}
+
+// crossover generates a child whose rounds come from a or b. Fixed rounds are taken from a.
+// Rounds which have pinned members are not mixed, because mixing may move pinned members.
+func (g *GeneticSolver) crossover(a, b *Schedule, rnd *rand.Rand) *Schedule {
+	child := newSchedule(a.members)
+	child.fixedRoundNum = a.fixedRoundNum
+	for r := range a.rounds {
+		var groups [][]int
+		switch {
+		case r < a.fixedRoundNum || rnd.Intn(3) == 0:
+			groups = copyGroups(a.rounds[r].groups)
+		case rnd.Intn(2) == 0 || a.rounds[r].pinned != nil:
+			groups = copyGroups(b.rounds[r].groups)
+		default:
+			groups = mixGroups(a.rounds[r], b.rounds[r], rnd)
+		}
+		// rounds of a and b consist of same members, so it never fails
+		_ = child.addRound(groups)
+		child.rounds[r].pinned = a.rounds[r].pinned
+	}
+	return child
+}
+
+func copyGroups(groups [][]int) [][]int {
+	c := make([][]int, len(groups))
+	for g, group := range groups {
+		c[g] = append([]int{}, group...)
+	}
+	return c
+}
+
+// mixGroups takes random groups of a and fills the other groups with members of b in the same position.
+// Members which are left over are moved to groups which have room, so that group sizes are same as a.
+func mixGroups(a, b *round, rnd *rand.Rand) [][]int {
+	groups := make([][]int, len(a.groups))
+	placed := map[int]bool{}
+	fromA := make([]bool, len(a.groups))
+	for g, group := range a.groups {
+		if rnd.Intn(2) == 0 {
+			continue
+		}
+		fromA[g] = true
+		groups[g] = append([]int{}, group...)
+		for _, m := range group {
+			placed[m] = true
+		}
+	}
+
+	var leftovers []int
+	for g := range a.groups {
+		if fromA[g] || g >= len(b.groups) {
+			continue
+		}
+		for _, m := range b.groups[g] {
+			if placed[m] || len(groups[g]) >= len(a.groups[g]) {
+				continue
+			}
+			groups[g] = append(groups[g], m)
+			placed[m] = true
+		}
+	}
+	for _, group := range a.groups {
+		for _, m := range group {
+			if !placed[m] {
+				leftovers = append(leftovers, m)
+			}
+		}
+	}
+
+	// repair group sizes
+	rnd.Shuffle(len(leftovers), func(i, j int) {
+		leftovers[i], leftovers[j] = leftovers[j], leftovers[i]
+	})
+	for g := range groups {
+		for len(groups[g]) < len(a.groups[g]) {
+			groups[g] = append(groups[g], leftovers[0])
+			leftovers = leftovers[1:]
+		}
+	}
+	return groups
+}
+
+// mutate swaps random members of s, and then tries swaps which do not make s worse
+func mutate(s *Schedule, cost Cost, rnd *rand.Rand) {
+	for i := rnd.Intn(3); i >= 0; i-- {
+		if r, a, b, ok := s.randomSwap(rnd); ok {
+			s.swap(r, a, b)
+		}
+	}
+	for i := 0; i < len(s.members); i++ {
+		r, a, b, ok := s.randomSwap(rnd)
+		if !ok {
+			return
+		}
+		if cost.SwapDelta(s, r, a, b) <= 0 {
+			s.swap(r, a, b)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func TestGeneticSolver_Solve(t *testing.T) {
+	tests := []struct {
+		name      string
+		memberNum int
+		roundNum  int
+		groupSize int
+		fixed     int
+		want      int
+	}{
+		{name: "affine plane of order 3", memberNum: 9, roundNum: 4, groupSize: 3, want: 0},
+		{name: "fixed rounds are kept", memberNum: 9, roundNum: 4, groupSize: 3, fixed: 2, want: 0},
+		{name: "duplication is unavoidable", memberNum: 4, roundNum: 4, groupSize: 2, want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rnd := rand.New(rand.NewSource(1))
+			s := newTestSchedule(t, tt.memberNum, tt.roundNum, tt.groupSize, rnd)
+			s.FixRounds(tt.fixed)
+			fixed := FormatGroupLines(s.members, s.GroupsList()[:tt.fixed])
+
+			got := NewGeneticSolver(30, 300).Solve(context.Background(), s, DupPairCost{}, rnd)
+			if got.CountDup() != tt.want {
+				t.Errorf("Solve() dup = %v, want %v", got.CountDup(), tt.want)
+			}
+			if gotFixed := FormatGroupLines(got.members, got.GroupsList()[:tt.fixed]); fmt.Sprint(gotFixed) != fmt.Sprint(fixed) {
+				t.Errorf("Solve() changed fixed rounds: got = %v, want %v", gotFixed, fixed)
+			}
+		})
+	}
+}
+
+func Test_mixGroups(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	s := newTestSchedule(t, 11, 2, 3, rnd)
+	for i := 0; i < 100; i++ {
+		groups := mixGroups(s.rounds[0], s.rounds[1], rnd)
+		seen := map[int]bool{}
+		for g, group := range groups {
+			if len(group) != len(s.rounds[0].groups[g]) {
+				t.Fatalf("mixGroups() group %d has %d members, want %d", g, len(group), len(s.rounds[0].groups[g]))
+			}
+			for _, m := range group {
+				if seen[m] {
+					t.Fatalf("mixGroups() member %d is placed twice: %v", m, groups)
+				}
+				seen[m] = true
+			}
+		}
+		if len(seen) != 11 {
+			t.Fatalf("mixGroups() has %d members, want 11", len(seen))
+		}
+	}
+}
@@ -3,11 +3,17 @@ package domain
 import (
 	"encoding/csv"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
 	"sort"
 	"strconv"
+	"strings"
 )
 
+// commentPrefix is prefix of comment lines of csv files
+const commentPrefix = '#'
+
 type GroupID int
 type Group struct {
 	ID      GroupID
@@ -40,6 +46,16 @@ func (g Groups) addGroup(member *Member, id GroupID) {
 	g[id].members = append(g[id].members, member)
 }
 
+func (g Groups) sortedIDList() (idList []GroupID) {
+	for id := range g {
+		idList = append(idList, id)
+	}
+	sort.Slice(idList, func(i, j int) bool {
+		return idList[i] < idList[j]
+	})
+	return
+}
+
 func (g Groups) addGroups(member *Member, idList ...GroupID) {
 	for _, id := range idList {
 		g.addGroup(member, id)
@@ -92,43 +108,238 @@ func CountDupMemberPairs(groupsList []Groups) (int, error) {
 	return pairMap.CountDup(), nil
 }
 
+// Meeting is a group which a pair of members shares in a round
+type Meeting struct {
+	// Round is 1-based index of the round
+	Round   int
+	GroupID GroupID
+}
+
+func (m *Meeting) String() string {
+	return fmt.Sprintf("round %d, group %d", m.Round, m.GroupID)
+}
+
+// DupPair is a pair of members who meet more than once
+type DupPair struct {
+	// Names are sorted in ascending order
+	Names    [2]string
+	Meetings []*Meeting
+}
+
+func (p *DupPair) String() string {
+	var meetings []string
+	for _, m := range p.Meetings {
+		meetings = append(meetings, m.String())
+	}
+	return fmt.Sprintf("%s and %s: %d times (%s)", p.Names[0], p.Names[1], len(p.Meetings), strings.Join(meetings, "; "))
+}
+
+// DupPairs returns pairs of members who meet more than once in groupsList.
+// Pairs are sorted by number of meetings in descending order, and then by names.
+func DupPairs(groupsList []Groups) []*DupPair {
+	found := map[[2]string]*DupPair{}
+	var pairs []*DupPair
+	for r, groups := range groupsList {
+		for _, id := range groups.sortedIDList() {
+			for _, pair := range groups[id].getMemberPairs() {
+				names := [2]string{pair[1].Name, pair[0].Name}
+				p, ok := found[names]
+				if !ok {
+					p = &DupPair{Names: names}
+					found[names] = p
+					pairs = append(pairs, p)
+				}
+				p.Meetings = append(p.Meetings, &Meeting{Round: r + 1, GroupID: id})
+			}
+		}
+	}
+
+	var dupPairs []*DupPair
+	for _, p := range pairs {
+		if len(p.Meetings) > 1 {
+			dupPairs = append(dupPairs, p)
+		}
+	}
+	sort.Slice(dupPairs, func(i, j int) bool {
+		if len(dupPairs[i].Meetings) != len(dupPairs[j].Meetings) {
+			return len(dupPairs[i].Meetings) > len(dupPairs[j].Meetings)
+		}
+		if dupPairs[i].Names[0] != dupPairs[j].Names[0] {
+			return dupPairs[i].Names[0] < dupPairs[j].Names[0]
+		}
+		return dupPairs[i].Names[1] < dupPairs[j].Names[1]
+	})
+	return dupPairs
+}
+
 func ParseGroupFile(filePath string) ([]Groups, error) {
+	_, groupsList, err := ParseGroupFileWithMembers(filePath)
+	return groupsList, err
+}
+
+// ParseGroupFileWithMembers parses group file and returns members in order of lines as well as groupsList
+func ParseGroupFileWithMembers(filePath string) ([]*Member, []Groups, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open file from %s", filePath)
+		return nil, nil, fmt.Errorf("failed to open file from %s", filePath)
 	}
 	defer file.Close()
 
 	reader := csv.NewReader(file)
+	reader.Comment = commentPrefix
 	lines, err := reader.ReadAll()
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse csv from %s: %w", filePath, err)
+		return nil, nil, fmt.Errorf("failed to parse csv from %s: %w", filePath, err)
 	}
-	return parseGroupLines(lines)
+	return parseGroupLinesWithMembers(lines)
+}
+
+// ParseGroupFileHeaders returns column names of rounds in group file, which are headers other than NAME
+func ParseGroupFileHeaders(filePath string) ([]string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file from %s", filePath)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.Comment = commentPrefix
+	headers, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse headers from %s: %w", filePath, err)
+	}
+	nameIndex, ok := findNameIndex(headers)
+	if !ok {
+		return nil, fmt.Errorf("failed to find NAME column")
+	}
+	return append(append([]string{}, headers[:nameIndex]...), headers[nameIndex+1:]...), nil
+}
+
+// ParseGroupFileComments returns comment lines of group file without the comment prefix.
+// Comments record how groups are generated.
+func ParseGroupFileComments(filePath string) ([]string, error) {
+	contents, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file from %s: %w", filePath, err)
+	}
+	var comments []string
+	for _, line := range strings.Split(string(contents), "\n") {
+		if strings.HasPrefix(line, string(commentPrefix)) {
+			comments = append(comments, strings.TrimSpace(strings.TrimPrefix(strings.TrimSuffix(line, "\r"), string(commentPrefix))))
+		}
+	}
+	return comments, nil
+}
+
+// WriteGroupFile writes comments and groupsList as group file which can be parsed by ParseGroupFile.
+// headers are column names of rounds, and rounds which do not have a header are named by ordinal numbers such as 1st.
+func WriteGroupFile(w io.Writer, comments []string, headers []string, members []*Member, groupsList []Groups) error {
+	for _, comment := range comments {
+		if _, err := fmt.Fprintf(w, "%c %s\n", commentPrefix, comment); err != nil {
+			return fmt.Errorf("failed to write comment: %w", err)
+		}
+	}
+	if err := csv.NewWriter(w).WriteAll(FormatGroupLinesWithHeaders(members, groupsList, headers)); err != nil {
+		return fmt.Errorf("failed to write groups: %w", err)
+	}
+	return nil
 }
 
 func parseGroupLines(lines [][]string) ([]Groups, error) {
+	_, groupsList, err := parseGroupLinesWithMembers(lines)
+	return groupsList, err
+}
+
+func parseGroupLinesWithMembers(lines [][]string) ([]*Member, []Groups, error) {
 	if err := validateMemberFile(lines); err != nil {
-		return nil, fmt.Errorf("failed to parse group file: %w", err)
+		return nil, nil, fmt.Errorf("failed to parse group file: %w", err)
 	}
 	headers := lines[0]
 	nameIndex, ok := findNameIndex(headers)
 	if !ok {
-		return nil, fmt.Errorf("failed to find NAME column")
+		return nil, nil, fmt.Errorf("failed to find NAME column")
 	}
 
+	var members []*Member
 	groupsList := newGroupsList(len(headers) - 1)
 	for _, line := range lines[1:] {
-		name, groupIDList, err := parseGroupLine(line, nameIndex)
+		name, groupIDs, err := parseGroupLine(line, nameIndex)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse group line from %s: %w", line, err)
+			return nil, nil, fmt.Errorf("failed to parse group line from %s: %w", line, err)
+		}
+
+		member := &Member{Name: name}
+		members = append(members, member)
+		for r, id := range groupIDs {
+			groupsList[r].addGroup(member, id)
+		}
+	}
+	return members, groupsList, nil
+}
+
+// FormatGroupLines converts groupsList to csv lines which can be parsed by ParseGroupFile.
+// Lines are ordered by members.
+func FormatGroupLines(members []*Member, groupsList []Groups) [][]string {
+	return FormatGroupLinesWithHeaders(members, groupsList, nil)
+}
+
+// FormatGroupLinesWithHeaders formats groupsList as lines of group file whose round columns are named by headers.
+// Rounds which do not have a header are named by ordinal numbers such as 1st.
+func FormatGroupLinesWithHeaders(members []*Member, groupsList []Groups, headers []string) [][]string {
+	nameHeaders := []string{"NAME"}
+	for i := range groupsList {
+		if i < len(headers) {
+			nameHeaders = append(nameHeaders, headers[i])
+		} else {
+			nameHeaders = append(nameHeaders, ordinal(i+1))
+		}
+	}
+	lines := [][]string{nameHeaders}
+
+	for _, member := range members {
+		line := []string{member.Name}
+		for _, groups := range groupsList {
+			line = append(line, groups.findGroupID(member.Name))
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func (g Groups) findGroupID(name string) string {
+	for id, group := range g {
+		for _, member := range group.members {
+			if member.Name == name {
+				return strconv.Itoa(int(id))
+			}
 		}
+	}
+	return ""
+}
 
-		for i, id := range groupIDList {
-			groupsList[i].addGroup(&Member{Name: name}, id)
+// groupIDMap returns map from member name to ID of the group which the member belongs to
+func (g Groups) groupIDMap() map[string]GroupID {
+	groupIDs := map[string]GroupID{}
+	for id, group := range g {
+		for _, member := range group.members {
+			groupIDs[member.Name] = id
 		}
 	}
-	return groupsList, nil
+	return groupIDs
+}
+
+func ordinal(n int) string {
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+	return strconv.Itoa(n) + suffix
 }
 
 func newGroupsList(length int) []Groups {
@@ -139,17 +350,24 @@ func newGroupsList(length int) []Groups {
 	return groupsList
 }
 
-func parseGroupLine(line []string, nameIndex int) (name string, groupIDList []GroupID, err error) {
+// parseGroupLine returns name and map from 0-based round index to group ID of the member.
+// Empty cell means that the member is absent in the round, so the round is not included in groupIDs.
+func parseGroupLine(line []string, nameIndex int) (name string, groupIDs map[int]GroupID, err error) {
+	groupIDs = map[int]GroupID{}
+	r := 0
 	for i, groupIDOrName := range line {
 		if i == nameIndex {
 			name = groupIDOrName
-		} else {
-			groupID, err := strconv.Atoi(groupIDOrName)
+			continue
+		}
+		if cell := strings.TrimSpace(groupIDOrName); cell != "" {
+			groupID, err := strconv.Atoi(cell)
 			if err != nil {
 				return "", nil, fmt.Errorf("failed to convert group ID to int from %s: %w", groupIDOrName, err)
 			}
-			groupIDList = append(groupIDList, GroupID(groupID))
+			groupIDs[r] = GroupID(groupID)
 		}
+		r++
 	}
 	return
 }
@@ -61,6 +61,47 @@ func Test_parseGroupLines(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "empty cells mean absent members",
+			args: args{
+				lines: [][]string{
+					{"NAME", "1st", "2nd"},
+					{"alice", "1", ""},
+					{"bob", "1", "1"},
+					{"carol", "", "1"},
+				},
+			},
+			want: []Groups{
+				{
+					1: &Group{
+						ID: 1,
+						members: []*Member{
+							{Name: "alice"},
+							{Name: "bob"},
+						},
+					},
+				},
+				{
+					1: &Group{
+						ID: 1,
+						members: []*Member{
+							{Name: "bob"},
+							{Name: "carol"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "group ID must be an integer",
+			args: args{
+				lines: [][]string{
+					{"NAME", "1st"},
+					{"alice", "x"},
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -174,3 +215,80 @@ func TestCountDupMemberPairs(t *testing.T) {
 		})
 	}
 }
+
+func TestDupPairs(t *testing.T) {
+	groupsList, err := parseGroupLines([][]string{
+		{"NAME", "1st", "2nd", "3rd"},
+		{"dave", "1", "1", "1"},
+		{"bob", "1", "1", "1"},
+		{"carol", "2", "1", "2"},
+		{"alice", "2", "1", "1"},
+		{"ellen", "2", "", "2"},
+	})
+	if err != nil {
+		t.Fatalf("failed to parse groups: %v", err)
+	}
+	got := DupPairs(groupsList)
+	want := []string{
+		"bob and dave: 3 times (round 1, group 1; round 2, group 1; round 3, group 1)",
+		"alice and bob: 2 times (round 2, group 1; round 3, group 1)",
+		"alice and carol: 2 times (round 1, group 2; round 2, group 1)",
+		"alice and dave: 2 times (round 2, group 1; round 3, group 1)",
+		"carol and ellen: 2 times (round 1, group 2; round 3, group 2)",
+	}
+	var gotStrings []string
+	for _, p := range got {
+		gotStrings = append(gotStrings, p.String())
+	}
+	if !reflect.DeepEqual(gotStrings, want) {
+		t.Errorf("DupPairs() = %q, want %q", gotStrings, want)
+	}
+}
+
+func TestFormatGroupLines(t *testing.T) {
+	lines := [][]string{
+		{"NAME", "1st", "2nd"},
+		{"alice", "1", "2"},
+		{"bob", "1", "2"},
+		{"carol", "2", "1"},
+		{"dave", "2", "1"},
+	}
+	groupsList, err := parseGroupLines(lines)
+	if err != nil {
+		t.Fatalf("failed to parse group lines: %v", err)
+	}
+
+	got := FormatGroupLines(newTestMembers("alice", "bob", "carol", "dave"), groupsList)
+	if !reflect.DeepEqual(got, lines) {
+		t.Errorf("FormatGroupLines() got = %v, want %v", got, lines)
+	}
+
+	got = FormatGroupLinesWithHeaders(newTestMembers("alice", "bob", "carol", "dave"), groupsList, []string{"2020-04-01"})
+	if want := []string{"NAME", "2020-04-01", "2nd"}; !reflect.DeepEqual(got[0], want) {
+		t.Errorf("FormatGroupLinesWithHeaders() headers = %v, want %v", got[0], want)
+	}
+}
+
+func Test_ordinal(t *testing.T) {
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{n: 1, want: "1st"},
+		{n: 2, want: "2nd"},
+		{n: 3, want: "3rd"},
+		{n: 4, want: "4th"},
+		{n: 11, want: "11th"},
+		{n: 12, want: "12th"},
+		{n: 13, want: "13th"},
+		{n: 21, want: "21st"},
+		{n: 112, want: "112th"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := ordinal(tt.n); got != tt.want {
+				t.Errorf("ordinal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
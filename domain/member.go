@@ -5,23 +5,49 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sort"
 	"strconv"
+	"strings"
+)
+
+// rolesColumn is the column of member file which has roles of members separated by roleSeparator
+const (
+	rolesColumn   = "ROLES"
+	roleSeparator = ";"
 )
 
 type MemberID int
 type Member struct {
 	ID   MemberID
 	Name string
+	// Attributes has values of columns other than ID, NAME and ROLES, such as department or location
+	Attributes map[string]string
+	// Roles are tags such as facilitator or manager
+	Roles []string
+}
+
+// HasRole returns true if the member has the role
+func (m *Member) HasRole(role string) bool {
+	for _, r := range m.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
 }
 
-func parseMemberFile(filePath string) ([]*Member, error) {
+// ParseMemberFile parses member csv file which has ID and NAME columns.
+// Optional ROLES column has roles of each member separated by semicolons, such as "facilitator;manager".
+// Other columns are parsed as attributes of members.
+func ParseMemberFile(filePath string) ([]*Member, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("failed to open file from %s", filePath)
 	}
 	defer file.Close()
 
 	reader := csv.NewReader(file)
+	reader.Comment = commentPrefix
 	lines, err := reader.ReadAll()
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse csv from %s: %w", filePath, err)
@@ -43,6 +69,10 @@ func parseMemberLines(lines [][]string) ([]*Member, error) {
 	if !ok {
 		return nil, fmt.Errorf("failed to find NAME column")
 	}
+	rolesIndex, ok := findColumnIndex(headers, rolesColumn)
+	if !ok {
+		rolesIndex = -1
+	}
 
 	var members []*Member
 	for _, d := range data {
@@ -50,11 +80,69 @@ func parseMemberLines(lines [][]string) ([]*Member, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse member line: %w", err)
 		}
+		for i, header := range headers {
+			if i == idIndex || i == nameIndex {
+				continue
+			}
+			if i == rolesIndex {
+				member.Roles = parseRoles(d[i])
+				continue
+			}
+			if member.Attributes == nil {
+				member.Attributes = map[string]string{}
+			}
+			member.Attributes[header] = strings.TrimSpace(d[i])
+		}
 		members = append(members, member)
 	}
 	return members, nil
 }
 
+// AttributeNames returns sorted names of attributes which members have, except for excluded ones
+func AttributeNames(members []*Member, excluded ...string) (names []string) {
+	found := map[string]bool{}
+	for _, name := range excluded {
+		found[name] = true
+	}
+	for _, member := range members {
+		for name := range member.Attributes {
+			if !found[name] {
+				found[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return
+}
+
+// CopyAttributes sets attributes and roles of members in roster to members which have same name.
+// Members parsed from group file do not have attributes, so they are taken from member file.
+func CopyAttributes(members, roster []*Member) error {
+	found := map[string]*Member{}
+	for _, member := range roster {
+		found[member.Name] = member
+	}
+	for _, member := range members {
+		m, ok := found[member.Name]
+		if !ok {
+			return fmt.Errorf("member is not found in member file: %s", member.Name)
+		}
+		member.Attributes = m.Attributes
+		member.Roles = m.Roles
+	}
+	return nil
+}
+
+func parseRoles(cell string) (roles []string) {
+	for _, role := range strings.Split(cell, roleSeparator) {
+		if role = strings.TrimSpace(role); role != "" {
+			roles = append(roles, role)
+		}
+	}
+	return
+}
+
 func parseMemberLine(line []string, idIndex, nameIndex int) (*Member, error) {
 	idStr := line[idIndex]
 	id, err := strconv.Atoi(idStr)
@@ -0,0 +1,226 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Objective is what generators minimize.
+// It is a weighted sum of terms whose weights are given by Weights.
+// Duplicated member pairs are always counted, and their penalties decay with rounds since the last meeting if Decay is set.
+// Pairs in PairWeights cost their weights for each meeting instead.
+// Each violation of hard pair and role Constraints costs more than the other terms can vary, and violations of soft ones cost their weights.
+// Pinned members of Constraints are never moved.
+// Each member outside of fair share of BalancedAttributes costs as much as a duplicated member pair.
+// Variance of total ratings of groups in each round is added if Rating is set, and average ratings are used instead if RatingAverage is true.
+// Satisfied Preferences reduce the cost by 1 for the first wanted member, 1/2 for the second and so on.
+// nil Objective counts only duplicated member pairs.
+type Objective struct {
+	// Decay decides penalty of a repeated meeting. Every repeat costs 1 if it is nil.
+	Decay       Decay
+	PairWeights []*PairWeight
+	Constraints *Constraints
+	// BalancedAttributes are names of attributes of members whose values are spread evenly across groups
+	BalancedAttributes []string
+	// Rating is name of a numeric attribute of members whose totals are balanced across groups
+	Rating        string
+	RatingAverage bool
+	Preferences   []*Preference
+	// Weights are weights of terms. Every term has weight 1 if it is nil.
+	Weights *ObjectiveWeights
+}
+
+// ObjectiveWeights are weights of terms of Objective except hard constraints
+type ObjectiveWeights struct {
+	Dup     float64
+	Balance float64
+	// Soft multiplies weights of soft constraints
+	Soft       float64
+	Rating     float64
+	Preference float64
+}
+
+// DefaultObjectiveWeights returns weights which weigh every term equally
+func DefaultObjectiveWeights() *ObjectiveWeights {
+	return &ObjectiveWeights{Dup: 1, Balance: 1, Soft: 1, Rating: 1, Preference: 1}
+}
+
+// ObjectiveTerm is a term of Objective which contributes Value multiplied by Weight to the total
+type ObjectiveTerm struct {
+	Name   string
+	Value  float64
+	Weight float64
+}
+
+// Score returns contribution of the term to the total
+func (t *ObjectiveTerm) Score() float64 {
+	return t.Value * t.Weight
+}
+
+type objectiveTerm struct {
+	name   string
+	cost   Cost
+	weight float64
+	// hard is true for violations of hard constraints, whose weight is decided by range of the other terms
+	hard bool
+}
+
+// terms returns terms of the objective for members. Terms which the objective does not have are omitted.
+func (o *Objective) terms(members []*Member) ([]*objectiveTerm, error) {
+	if o == nil {
+		return []*objectiveTerm{{name: "duplicated member pairs", cost: DupPairCost{}, weight: 1}}, nil
+	}
+	weights := o.Weights
+	if weights == nil {
+		weights = DefaultObjectiveWeights()
+	}
+
+	dup := &objectiveTerm{name: "duplicated member pairs", cost: DupPairCost{}, weight: weights.Dup}
+	var dupCost pairTerm = DupPairCost{}
+	if o.Decay != nil {
+		dupCost = &decayedDupCost{decay: o.Decay}
+		dup.name, dup.cost = "time-decayed duplicated member pairs", dupCost
+	}
+	if len(o.PairWeights) > 0 {
+		weightCost, err := newPairWeightCost(members, dupCost, o.PairWeights)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pair weights: %w", err)
+		}
+		dup.name, dup.cost = dup.name+" with pair weights", weightCost
+	}
+	terms := []*objectiveTerm{dup}
+
+	hard, soft := o.Constraints.partition()
+	for _, c := range []struct {
+		name        string
+		constraints *Constraints
+		weight      float64
+		hard        bool
+	}{
+		{name: "hard constraint violations", constraints: hard, hard: true},
+		{name: "soft constraint violations", constraints: soft, weight: weights.Soft},
+	} {
+		var costs Costs
+		if len(c.constraints.Pairs) > 0 {
+			pairCost, err := newPairConstraintCost(members, c.constraints.Pairs)
+			if err != nil {
+				return nil, fmt.Errorf("invalid constraints: %w", err)
+			}
+			costs = append(costs, pairCost)
+		}
+		if len(c.constraints.Roles) > 0 {
+			roleCost, err := newRoleConstraintCost(members, c.constraints.Roles)
+			if err != nil {
+				return nil, fmt.Errorf("invalid constraints: %w", err)
+			}
+			costs = append(costs, roleCost)
+		}
+		if len(costs) > 0 {
+			terms = append(terms, &objectiveTerm{name: c.name, cost: costs, weight: c.weight, hard: c.hard})
+		}
+	}
+
+	if len(o.BalancedAttributes) > 0 {
+		attributeCost, err := newAttributeCost(members, o.BalancedAttributes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid attributes to balance: %w", err)
+		}
+		terms = append(terms, &objectiveTerm{name: "attribute imbalance", cost: attributeCost, weight: weights.Balance})
+	}
+	if o.Rating != "" {
+		ratingCost, err := newRatingCost(members, o.Rating, o.RatingAverage)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rating: %w", err)
+		}
+		terms = append(terms, &objectiveTerm{name: "rating variance", cost: ratingCost, weight: weights.Rating})
+	}
+	if len(o.Preferences) > 0 {
+		preferenceCost, err := newPreferenceCost(members, o.Preferences)
+		if err != nil {
+			return nil, fmt.Errorf("invalid preferences: %w", err)
+		}
+		terms = append(terms, &objectiveTerm{name: "satisfied preferences", cost: preferenceCost, weight: weights.Preference})
+	}
+	return terms, nil
+}
+
+// newCost returns Cost of the objective for members
+func (o *Objective) newCost(members []*Member) (Cost, error) {
+	terms, err := o.terms(members)
+	if err != nil {
+		return nil, err
+	}
+	softRoundRange := softRoundRange(terms, len(members))
+	var costs Costs
+	for _, term := range terms {
+		if term.hard {
+			costs = append(costs, &hardConstraintCost{Term: term.cost, softRoundRange: softRoundRange})
+		} else if term.weight == 1 {
+			costs = append(costs, term.cost)
+		} else {
+			costs = append(costs, &WeightedCost{Term: term.cost, Weight: term.weight})
+		}
+	}
+	if len(costs) == 1 {
+		return costs[0], nil
+	}
+	return costs, nil
+}
+
+// Breakdown returns value of each term of the objective for s. Score of the objective is sum of scores of the terms.
+func (o *Objective) Breakdown(s *Schedule) ([]*ObjectiveTerm, error) {
+	terms, err := o.terms(s.members)
+	if err != nil {
+		return nil, err
+	}
+	softRoundRange := softRoundRange(terms, len(s.members))
+	var breakdown []*ObjectiveTerm
+	for _, term := range terms {
+		weight := term.weight
+		if term.hard {
+			weight = hardConstraintWeight(softRoundRange, len(s.rounds))
+		}
+		breakdown = append(breakdown, &ObjectiveTerm{Name: term.name, Value: term.cost.Cost(s), Weight: weight})
+	}
+	return breakdown, nil
+}
+
+// softRoundRange returns how much weighted terms other than hard constraints can vary per round
+func softRoundRange(terms []*objectiveTerm, memberNum int) (total float64) {
+	for _, term := range terms {
+		if !term.hard {
+			total += term.weight * roundRange(term.cost, memberNum)
+		}
+	}
+	return
+}
+
+// pinnedGroups returns groups of members pinned by constraints keyed by 0-based round
+func (o *Objective) pinnedGroups(members []*Member) (map[int]map[int]int, error) {
+	if o == nil {
+		return nil, nil
+	}
+	pinned, err := o.Constraints.pinnedGroups(members)
+	if err != nil {
+		return nil, fmt.Errorf("invalid constraints: %w", err)
+	}
+	return pinned, nil
+}
+
+// check returns error if rounds of s which are not fixed violate hard constraints
+func (o *Objective) check(s *Schedule) error {
+	if o == nil {
+		return nil
+	}
+	var messages []string
+	for _, v := range o.Constraints.Violations(s.GroupsList()) {
+		// soft constraints may be violated
+		if v.Round > s.fixedRoundNum && v.Weight == 0 {
+			messages = append(messages, v.String())
+		}
+	}
+	if len(messages) > 0 {
+		return fmt.Errorf("failed to satisfy constraints. more iterations or another strategy may help:\n%s", strings.Join(messages, "\n"))
+	}
+	return nil
+}
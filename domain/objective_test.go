@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"context"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestObjective_Breakdown(t *testing.T) {
+	members, groupsList, err := parseGroupLinesWithMembers([][]string{
+		{"NAME", "1st", "2nd"},
+		{"alice", "1", "1"},
+		{"bob", "1", "1"},
+		{"carol", "2", "2"},
+		{"dave", "2", "2"},
+	})
+	if err != nil {
+		t.Fatalf("failed to parse group lines: %v", err)
+	}
+	for i, team := range []string{"a", "a", "b", "b"} {
+		members[i].Attributes = map[string]string{"team": team}
+	}
+	s, err := NewScheduleFromGroupsList(members, groupsList)
+	if err != nil {
+		t.Fatalf("failed to create schedule: %v", err)
+	}
+	objective := &Objective{
+		Constraints: &Constraints{Pairs: []*PairConstraint{
+			{Type: Apart, Names: [2]string{"alice", "bob"}},
+			{Type: Together, Names: [2]string{"alice", "carol"}, Weight: 1.5},
+		}},
+		BalancedAttributes: []string{"team"},
+		Weights:            &ObjectiveWeights{Dup: 2, Balance: 0.5, Soft: 1},
+	}
+	got, err := objective.Breakdown(s)
+	if err != nil {
+		t.Fatalf("Breakdown() error = %v", err)
+	}
+	want := []*ObjectiveTerm{
+		{Name: "duplicated member pairs", Value: 2, Weight: 2},
+		// 1 + 2 rounds * (2 * 6 pairs + 1.5 of soft constraint + 0.5 * 2 * 4 members)
+		{Name: "hard constraint violations", Value: 2, Weight: 36},
+		{Name: "soft constraint violations", Value: 3, Weight: 1},
+		{Name: "attribute imbalance", Value: 8, Weight: 0.5},
+	}
+	if !reflect.DeepEqual(got, want) {
+		for _, term := range got {
+			t.Logf("%+v", term)
+		}
+		t.Fatalf("Breakdown() = %v, want %v", got, want)
+	}
+
+	cost, err := objective.newCost(members)
+	if err != nil {
+		t.Fatalf("newCost() error = %v", err)
+	}
+	total := 0.0
+	for _, term := range got {
+		total += term.Score()
+	}
+	if cost.Cost(s) != total {
+		t.Errorf("Cost() = %v, want sum of scores of terms %v", cost.Cost(s), total)
+	}
+}
+
+func TestObjective_Breakdown_Nil(t *testing.T) {
+	s := newTestSchedule(t, 4, 2, 2, rand.New(rand.NewSource(1)))
+	var objective *Objective
+	got, err := objective.Breakdown(s)
+	if err != nil {
+		t.Fatalf("Breakdown() error = %v", err)
+	}
+	if len(got) != 1 || got[0].Weight != 1 || got[0].Value != float64(s.CountDup()) {
+		t.Errorf("Breakdown() = %v, want only duplicated member pairs", got)
+	}
+}
+
+func TestGenerateGroups_HardConstraintsOutweighRating(t *testing.T) {
+	members := newTestMembers("alice", "bob", "carol", "dave", "ellen", "frank", "grace", "heidi")
+	for i, member := range members {
+		rating := "0"
+		if i < 2 {
+			rating = "10000"
+		}
+		member.Attributes = map[string]string{"skill": rating}
+	}
+	// keeping alice and bob together makes variance of ratings 10000^2, which any fixed penalty can hardly exceed
+	constraints := &Constraints{Pairs: []*PairConstraint{{Type: Together, Names: [2]string{"alice", "bob"}}}}
+	objective := &Objective{Constraints: constraints, Rating: "skill"}
+	rnd := rand.New(rand.NewSource(1))
+	got, err := GenerateGroups(context.Background(), members, nil, 1, NewGroupSizes(4), 10, objective, NewAnnealingSolver(10000), rnd)
+	if err != nil {
+		t.Fatalf("GenerateGroups() error = %v", err)
+	}
+	if violations := constraints.Violations(got); len(violations) != 0 {
+		t.Errorf("GenerateGroups() violates constraints: %v", violations)
+	}
+}
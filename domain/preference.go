@@ -0,0 +1,203 @@
+package domain
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Preference is a ranked list of members whom a member wants to meet
+type Preference struct {
+	Name string
+	// Wants are names of members in order of preference
+	Wants []string
+}
+
+// preferenceReward returns reward of meeting the member at 0-based rank. Higher ranked members are worth more.
+func preferenceReward(rank int) float64 {
+	return 1 / float64(rank+1)
+}
+
+// ParsePreferenceFile parses preference csv file.
+// Each line has a name followed by names of members whom the member wants to meet in order of preference, such as "alice,bob,carol".
+func ParsePreferenceFile(filePath string) ([]*Preference, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file from %s", filePath)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.Comment = commentPrefix
+	// number of wanted members differs by member
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	lines, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse csv from %s: %w", filePath, err)
+	}
+	return parsePreferenceLines(lines)
+}
+
+func parsePreferenceLines(lines [][]string) ([]*Preference, error) {
+	var preferences []*Preference
+	found := map[string]bool{}
+	for i, line := range lines {
+		name := strings.TrimSpace(line[0])
+		if found[name] {
+			return nil, fmt.Errorf("preferences of %s are specified more than once at line %d", name, i+1)
+		}
+		found[name] = true
+		p := &Preference{Name: name}
+		wanted := map[string]bool{}
+		for _, cell := range line[1:] {
+			want := strings.TrimSpace(cell)
+			switch {
+			case want == "":
+				continue
+			case want == name:
+				return nil, fmt.Errorf("%s wants to meet oneself at line %d", name, i+1)
+			case wanted[want]:
+				return nil, fmt.Errorf("%s wants to meet %s more than once at line %d", name, want, i+1)
+			}
+			wanted[want] = true
+			p.Wants = append(p.Wants, want)
+		}
+		preferences = append(preferences, p)
+	}
+	return preferences, nil
+}
+
+// PreferenceSatisfaction is how many preferences of a member are satisfied
+type PreferenceSatisfaction struct {
+	Name string
+	// Met and NotMet are wanted members in order of preference
+	Met    []string
+	NotMet []string
+}
+
+// PreferenceSatisfactions returns satisfaction of each preference in groupsList.
+// A preference is satisfied if the members are in the same group in any round.
+func PreferenceSatisfactions(groupsList []Groups, preferences []*Preference) []*PreferenceSatisfaction {
+	var satisfactions []*PreferenceSatisfaction
+	for _, p := range preferences {
+		satisfaction := &PreferenceSatisfaction{Name: p.Name}
+		for _, want := range p.Wants {
+			if meetsInAnyRound(groupsList, p.Name, want) {
+				satisfaction.Met = append(satisfaction.Met, want)
+			} else {
+				satisfaction.NotMet = append(satisfaction.NotMet, want)
+			}
+		}
+		satisfactions = append(satisfactions, satisfaction)
+	}
+	return satisfactions
+}
+
+func meetsInAnyRound(groupsList []Groups, a, b string) bool {
+	for _, groups := range groupsList {
+		ids := groups.groupIDMap()
+		idA, okA := ids[a]
+		idB, okB := ids[b]
+		if okA && okB && idA == idB {
+			return true
+		}
+	}
+	return false
+}
+
+// preferenceCost is a Cost which rewards pairs which meet at least once, so it is not positive
+type preferenceCost struct {
+	// rewards[a][b] is sum of rewards of preferences of a for b and b for a
+	rewards [][]float64
+	total   float64
+}
+
+func newPreferenceCost(members []*Member, preferences []*Preference) (*preferenceCost, error) {
+	indexes := map[string]int{}
+	for i, member := range members {
+		indexes[member.Name] = i
+	}
+	c := &preferenceCost{rewards: make([][]float64, len(members))}
+	for i := range c.rewards {
+		c.rewards[i] = make([]float64, len(members))
+	}
+	for _, p := range preferences {
+		a, ok := indexes[p.Name]
+		if !ok {
+			return nil, fmt.Errorf("unknown member in preferences: %s", p.Name)
+		}
+		for rank, want := range p.Wants {
+			b, ok := indexes[want]
+			if !ok {
+				return nil, fmt.Errorf("unknown member in preferences of %s: %s", p.Name, want)
+			}
+			reward := preferenceReward(rank)
+			c.rewards[a][b] += reward
+			c.rewards[b][a] += reward
+			c.total += reward
+		}
+	}
+	return c, nil
+}
+
+// Cost returns negative sum of rewards of satisfied preferences
+func (c *preferenceCost) Cost(s *Schedule) float64 {
+	total := 0.0
+	for a := range c.rewards {
+		for b := a + 1; b < len(c.rewards); b++ {
+			if s.meets[a][b] > 0 {
+				total -= c.rewards[a][b]
+			}
+		}
+	}
+	return total
+}
+
+// SwapDelta returns difference of Cost caused by swap.
+// Rewards change only if a pair meets for the first time or stops meeting at all.
+func (c *preferenceCost) SwapDelta(s *Schedule, r, a, b int) float64 {
+	rd := s.rounds[r]
+	ga, gb := rd.groupIndex[a], rd.groupIndex[b]
+	if ga == gb {
+		return 0
+	}
+	delta := 0.0
+	// member a leaves group ga and joins group gb, and vice versa
+	for _, m := range rd.groups[ga] {
+		if m != a {
+			delta += c.leaveDelta(s, a, m) + c.joinDelta(s, b, m)
+		}
+	}
+	for _, m := range rd.groups[gb] {
+		if m != b {
+			delta += c.leaveDelta(s, b, m) + c.joinDelta(s, a, m)
+		}
+	}
+	return delta
+}
+
+func (c *preferenceCost) leaveDelta(s *Schedule, a, b int) float64 {
+	if s.meets[a][b] == 1 {
+		return c.rewards[a][b]
+	}
+	return 0
+}
+
+func (c *preferenceCost) joinDelta(s *Schedule, a, b int) float64 {
+	if s.meets[a][b] == 0 {
+		return -c.rewards[a][b]
+	}
+	return 0
+}
+
+// roundRange returns sum of all rewards, which are given at most once in the whole schedule
+func (c *preferenceCost) roundRange(memberNum int) float64 {
+	return c.total
+}
+
+// lowerBound returns the cost when every preference is satisfied
+func (c *preferenceCost) lowerBound() float64 {
+	return -c.total
+}
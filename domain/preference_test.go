@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"context"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func Test_parsePreferenceLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		lines   [][]string
+		want    []*Preference
+		wantErr bool
+	}{
+		{
+			name:  "ranked names",
+			lines: [][]string{{"alice", "bob", " carol", ""}, {"bob"}},
+			want: []*Preference{
+				{Name: "alice", Wants: []string{"bob", "carol"}},
+				{Name: "bob"},
+			},
+		},
+		{name: "oneself", lines: [][]string{{"alice", "alice"}}, wantErr: true},
+		{name: "duplicated wanted member", lines: [][]string{{"alice", "bob", "bob"}}, wantErr: true},
+		{name: "duplicated member", lines: [][]string{{"alice", "bob"}, {"alice", "carol"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePreferenceLines(tt.lines)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePreferenceLines() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parsePreferenceLines() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPreferenceSatisfactions(t *testing.T) {
+	groupsList, err := parseGroupLines([][]string{
+		{"NAME", "1st", "2nd"},
+		{"alice", "1", "1"},
+		{"bob", "1", "2"},
+		{"carol", "2", "1"},
+		{"dave", "2", ""},
+	})
+	if err != nil {
+		t.Fatalf("failed to parse group lines: %v", err)
+	}
+	got := PreferenceSatisfactions(groupsList, []*Preference{
+		{Name: "alice", Wants: []string{"dave", "carol", "bob"}},
+		{Name: "dave", Wants: []string{"carol"}},
+	})
+	want := []*PreferenceSatisfaction{
+		{Name: "alice", Met: []string{"carol", "bob"}, NotMet: []string{"dave"}},
+		{Name: "dave", Met: []string{"carol"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("PreferenceSatisfactions() = %v, want %v", got, want)
+	}
+}
+
+func Test_preferenceCost_SwapDelta(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	s := newTestSchedule(t, 12, 3, 4, rnd)
+	var preferences []*Preference
+	for i := 0; i < 8; i++ {
+		preferences = append(preferences, &Preference{
+			Name:  s.members[i].Name,
+			Wants: []string{s.members[(i+1)%12].Name, s.members[(i+5)%12].Name, s.members[(i+7)%12].Name},
+		})
+	}
+	cost, err := newPreferenceCost(s.members, preferences)
+	if err != nil {
+		t.Fatalf("failed to create cost: %v", err)
+	}
+	assertSwapDelta(t, s, cost, rnd, 200)
+}
+
+func TestGenerateGroups_Preferences(t *testing.T) {
+	members := newTestMembers("alice", "bob", "carol", "dave", "ellen", "frank", "grace", "heidi", "ivan")
+	preferences := []*Preference{
+		{Name: "alice", Wants: []string{"bob", "carol", "dave", "ellen"}},
+		{Name: "frank", Wants: []string{"grace"}},
+		{Name: "ivan", Wants: []string{"frank"}},
+	}
+	rnd := rand.New(rand.NewSource(1))
+	got, err := GenerateGroups(context.Background(), members, nil, 2, NewGroupSizes(3), 10, &Objective{Preferences: preferences}, NewAnnealingSolver(100000), rnd)
+	if err != nil {
+		t.Fatalf("GenerateGroups() error = %v", err)
+	}
+	// 4 + 1 + 1 preferences can be satisfied in 2 rounds without duplicated member pairs
+	for _, satisfaction := range PreferenceSatisfactions(got, preferences) {
+		if len(satisfaction.NotMet) > 0 {
+			t.Errorf("preferences of %s are not satisfied: %v", satisfaction.Name, satisfaction.NotMet)
+		}
+	}
+	if dup, _ := CountDupMemberPairs(got); dup != 0 {
+		t.Errorf("GenerateGroups() dup = %v, want 0", dup)
+	}
+}
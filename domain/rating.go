@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// ratingCost is a Cost which sums variance of total ratings of groups in each round.
+// Average ratings of groups are used instead of totals if average is true, so that groups of different sizes are comparable.
+type ratingCost struct {
+	// ratings[m] is rating of member m
+	ratings []float64
+	average bool
+}
+
+// newRatingCost returns ratingCost of attribute name. Every member must have a numeric value of the attribute.
+func newRatingCost(members []*Member, name string, average bool) (*ratingCost, error) {
+	ratings, err := parseRatings(members, name)
+	if err != nil {
+		return nil, err
+	}
+	return &ratingCost{ratings: ratings, average: average}, nil
+}
+
+func parseRatings(members []*Member, name string) ([]float64, error) {
+	ratings := make([]float64, len(members))
+	for m, member := range members {
+		value, ok := member.Attributes[name]
+		if !ok {
+			return nil, fmt.Errorf("%s does not have rating column: %s", member.Name, name)
+		}
+		rating, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("rating of %s is not a number(%s): %w", member.Name, value, err)
+		}
+		ratings[m] = rating
+	}
+	return ratings, nil
+}
+
+// share returns contribution of rating to rating of a group of size
+func (c *ratingCost) share(rating float64, size int) float64 {
+	if c.average {
+		return rating / float64(size)
+	}
+	return rating
+}
+
+// roundRatings returns rating of each group of rd
+func (c *ratingCost) roundRatings(rd *round) []float64 {
+	ratings := make([]float64, len(rd.groups))
+	for g, group := range rd.groups {
+		total := 0.0
+		for _, m := range group {
+			total += c.ratings[m]
+		}
+		ratings[g] = c.share(total, len(group))
+	}
+	return ratings
+}
+
+// Cost returns sum of variance of group ratings of each round
+func (c *ratingCost) Cost(s *Schedule) float64 {
+	total := 0.0
+	for _, rd := range s.rounds {
+		total += variance(c.roundRatings(rd))
+	}
+	return total
+}
+
+// SwapDelta returns difference of variance of the round caused by swap.
+// Only ratings of groups of a and b change.
+func (c *ratingCost) SwapDelta(s *Schedule, r, a, b int) float64 {
+	rd := s.rounds[r]
+	ga, gb := rd.groupIndex[a], rd.groupIndex[b]
+	if ga == gb || c.ratings[a] == c.ratings[b] {
+		return 0
+	}
+	ratings := c.roundRatings(rd)
+	before := variance(ratings)
+	// group of a gets rating of b, and vice versa
+	diff := c.ratings[b] - c.ratings[a]
+	ratings[ga] += c.share(diff, len(rd.groups[ga]))
+	ratings[gb] -= c.share(diff, len(rd.groups[gb]))
+	return variance(ratings) - before
+}
+
+// roundRange returns square of sum of absolute ratings.
+// Ratings of groups are within the sum on both sides, so their variance is within the square.
+func (c *ratingCost) roundRange(memberNum int) float64 {
+	total := 0.0
+	for _, rating := range c.ratings {
+		total += math.Abs(rating)
+	}
+	return total * total
+}
+
+// variance returns population variance of values
+func variance(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	mean := 0.0
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	total := 0.0
+	for _, v := range values {
+		total += (v - mean) * (v - mean)
+	}
+	return total / float64(len(values))
+}
+
+// GroupRatings returns total rating of each group in each round of groupsList, or average rating if average is true.
+// Ratings are values of attribute name of members.
+func GroupRatings(members []*Member, groupsList []Groups, name string, average bool) ([][]float64, error) {
+	s, err := NewScheduleFromGroupsList(members, groupsList)
+	if err != nil {
+		return nil, err
+	}
+	c, err := newRatingCost(members, name, average)
+	if err != nil {
+		return nil, err
+	}
+	ratingsList := make([][]float64, len(s.rounds))
+	for r, rd := range s.rounds {
+		ratingsList[r] = c.roundRatings(rd)
+	}
+	return ratingsList, nil
+}
+
+// RatingSpread returns difference between the highest and the lowest ratings
+func RatingSpread(ratings []float64) float64 {
+	if len(ratings) == 0 {
+		return 0
+	}
+	lowest, highest := ratings[0], ratings[0]
+	for _, rating := range ratings[1:] {
+		if rating < lowest {
+			lowest = rating
+		}
+		if rating > highest {
+			highest = rating
+		}
+	}
+	return highest - lowest
+}
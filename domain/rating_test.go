@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestGroupRatings(t *testing.T) {
+	members := newTestMembersWithAttributes(
+		map[string]string{"skill": "1"},
+		map[string]string{"skill": "2"},
+		map[string]string{"skill": "3.5"},
+		map[string]string{"skill": "4"},
+		map[string]string{"skill": "5"},
+	)
+	groupsList, err := parseGroupLines([][]string{
+		{"NAME", "1st", "2nd"},
+		{"member0", "1", "1"},
+		{"member1", "1", "2"},
+		{"member2", "2", "1"},
+		{"member3", "2", "2"},
+		{"member4", "1", ""},
+	})
+	if err != nil {
+		t.Fatalf("failed to parse groups: %v", err)
+	}
+	cases := []struct {
+		average bool
+		want    [][]float64
+	}{
+		{average: false, want: [][]float64{{8, 7.5}, {4.5, 6}}},
+		{average: true, want: [][]float64{{8.0 / 3, 3.75}, {2.25, 3}}},
+	}
+	for _, c := range cases {
+		got, err := GroupRatings(members, groupsList, "skill", c.average)
+		if err != nil {
+			t.Fatalf("GroupRatings() error = %v", err)
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("GroupRatings(average: %v) = %v, want %v", c.average, got, c.want)
+		}
+	}
+	if got := RatingSpread([]float64{8, 7.5, 9}); got != 1.5 {
+		t.Errorf("RatingSpread() = %v, want 1.5", got)
+	}
+}
+
+func Test_newRatingCost_Invalid(t *testing.T) {
+	cases := [][]*Member{
+		newTestMembersWithAttributes(map[string]string{"skill": "1"}, map[string]string{"skill": "high"}),
+		newTestMembersWithAttributes(map[string]string{"skill": "1"}, map[string]string{"level": "1"}),
+	}
+	for _, members := range cases {
+		if _, err := newRatingCost(members, "skill", false); err == nil {
+			t.Errorf("newRatingCost() must return error for %v", members[1].Attributes)
+		}
+	}
+}
+
+func Test_ratingCost_SwapDelta(t *testing.T) {
+	for _, average := range []bool{false, true} {
+		t.Run(fmt.Sprintf("average %v", average), func(t *testing.T) {
+			rnd := rand.New(rand.NewSource(1))
+			// group sizes are 4, 4 and 3
+			s := newTestSchedule(t, 11, 3, 4, rnd)
+			for i, member := range s.members {
+				member.Attributes = map[string]string{"skill": fmt.Sprint(i * i % 7)}
+			}
+			cost, err := newRatingCost(s.members, "skill", average)
+			if err != nil {
+				t.Fatalf("failed to create cost: %v", err)
+			}
+			assertSwapDelta(t, s, cost, rnd, 200)
+		})
+	}
+}
+
+func TestGenerateGroups_Rating(t *testing.T) {
+	var attributes []map[string]string
+	for i := 1; i <= 8; i++ {
+		attributes = append(attributes, map[string]string{"skill": fmt.Sprint(i)})
+	}
+	members := newTestMembersWithAttributes(attributes...)
+	rnd := rand.New(rand.NewSource(1))
+	objective := &Objective{Rating: "skill"}
+	got, err := GenerateGroups(context.Background(), members, nil, 2, NewGroupSizes(4), 10, objective, NewAnnealingSolver(100000), rnd)
+	if err != nil {
+		t.Fatalf("GenerateGroups() error = %v", err)
+	}
+	ratingsList, err := GroupRatings(members, got, "skill", false)
+	if err != nil {
+		t.Fatalf("failed to evaluate ratings: %v", err)
+	}
+	// both rounds can be split into two groups of total 18 with the fewest duplicated member pairs
+	for r, ratings := range ratingsList {
+		if spread := RatingSpread(ratings); spread != 0 {
+			t.Errorf("GenerateGroups() ratings of round %d = %v, want same totals", r+1, ratings)
+		}
+	}
+	if dup, _ := CountDupMemberPairs(got); dup != 4 {
+		t.Errorf("GenerateGroups() dup = %v, want 4", dup)
+	}
+}
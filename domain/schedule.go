@@ -0,0 +1,368 @@
+package domain
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+// Schedule is an index based representation of []Groups.
+// Solvers mutate a Schedule in place and convert it back to []Groups by GroupsList.
+type Schedule struct {
+	members       []*Member
+	rounds        []*round
+	meets         [][]int
+	fixedRoundNum int
+}
+
+type round struct {
+	groupIndex []int
+	groups     [][]int
+	// pinned[m] is true if solvers must not move member m. It is nil if no member is pinned in the round.
+	pinned []bool
+}
+
+// together returns true if member a and member b are in the same group
+func (rd *round) together(a, b int) bool {
+	return rd.groupIndex[a] != -1 && rd.groupIndex[a] == rd.groupIndex[b]
+}
+
+func (rd *round) isPinned(m int) bool {
+	return rd.pinned != nil && rd.pinned[m]
+}
+
+func newSchedule(members []*Member) *Schedule {
+	meets := make([][]int, len(members))
+	for i := range meets {
+		meets[i] = make([]int, len(members))
+	}
+	return &Schedule{members: members, meets: meets}
+}
+
+// NewScheduleFromGroupsList generates Schedule from members and groupsList.
+// Every member which appears in groupsList must be included in members.
+func NewScheduleFromGroupsList(members []*Member, groupsList []Groups) (*Schedule, error) {
+	indexes := map[string]int{}
+	for i, member := range members {
+		if _, ok := indexes[member.Name]; ok {
+			return nil, fmt.Errorf("duplicated member name: %s", member.Name)
+		}
+		indexes[member.Name] = i
+	}
+
+	s := newSchedule(members)
+	for _, groups := range groupsList {
+		var memberGroups [][]int
+		for _, id := range groups.sortedIDList() {
+			var group []int
+			for _, member := range groups[id].members {
+				index, ok := indexes[member.Name]
+				if !ok {
+					return nil, fmt.Errorf("unknown member is included in group %d: %s", id, member.Name)
+				}
+				group = append(group, index)
+			}
+			memberGroups = append(memberGroups, group)
+		}
+		if err := s.addRound(memberGroups); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Members returns members of the schedule
+func (s *Schedule) Members() []*Member {
+	return s.members
+}
+
+// RoundNum returns number of rounds
+func (s *Schedule) RoundNum() int {
+	return len(s.rounds)
+}
+
+// FixRounds makes first n rounds unchangeable by solvers
+func (s *Schedule) FixRounds(n int) {
+	s.fixedRoundNum = n
+}
+
+// CapacitiesList returns sizes of groups of each round
+func (s *Schedule) CapacitiesList() [][]int {
+	capacitiesList := make([][]int, len(s.rounds))
+	for r, rd := range s.rounds {
+		for _, group := range rd.groups {
+			capacitiesList[r] = append(capacitiesList[r], len(group))
+		}
+	}
+	return capacitiesList
+}
+
+// GroupsList converts the schedule to []Groups.
+// GroupID of each group is its 1-based index in the round.
+func (s *Schedule) GroupsList() []Groups {
+	groupsList := newGroupsList(len(s.rounds))
+	for r, rd := range s.rounds {
+		for g, group := range rd.groups {
+			id := GroupID(g + 1)
+			groupsList[r][id] = &Group{ID: id}
+			for _, m := range group {
+				groupsList[r].addGroup(s.members[m], id)
+			}
+		}
+	}
+	return groupsList
+}
+
+// CountDup returns number of duplicated member pairs in the schedule
+func (s *Schedule) CountDup() (cnt int) {
+	for i := range s.meets {
+		for j := i + 1; j < len(s.meets); j++ {
+			if s.meets[i][j] > 1 {
+				cnt += s.meets[i][j] - 1
+			}
+		}
+	}
+	return
+}
+
+func (s *Schedule) addRound(groups [][]int) error {
+	groupIndex := make([]int, len(s.members))
+	for i := range groupIndex {
+		groupIndex[i] = -1
+	}
+	for g, group := range groups {
+		for _, m := range group {
+			if groupIndex[m] != -1 {
+				return fmt.Errorf("member %s belongs to multiple groups in round %d", s.members[m].Name, len(s.rounds)+1)
+			}
+			groupIndex[m] = g
+		}
+	}
+
+	s.rounds = append(s.rounds, &round{groupIndex: groupIndex, groups: groups})
+	for _, group := range groups {
+		s.addMeets(group, 1)
+	}
+	return nil
+}
+
+func (s *Schedule) addMeets(group []int, diff int) {
+	for i, a := range group {
+		for _, b := range group[i+1:] {
+			s.meets[a][b] += diff
+			s.meets[b][a] += diff
+		}
+	}
+}
+
+// swap exchanges groups of member a and member b in round r
+func (s *Schedule) swap(r, a, b int) {
+	rd := s.rounds[r]
+	ga, gb := rd.groupIndex[a], rd.groupIndex[b]
+	if ga == gb {
+		return
+	}
+	for _, m := range rd.groups[ga] {
+		if m != a {
+			s.meets[a][m]--
+			s.meets[m][a]--
+			s.meets[b][m]++
+			s.meets[m][b]++
+		}
+	}
+	for _, m := range rd.groups[gb] {
+		if m != b {
+			s.meets[b][m]--
+			s.meets[m][b]--
+			s.meets[a][m]++
+			s.meets[m][a]++
+		}
+	}
+	replace(rd.groups[ga], a, b)
+	replace(rd.groups[gb], b, a)
+	rd.groupIndex[a], rd.groupIndex[b] = gb, ga
+}
+
+// randomSwap returns a round and two members which belong to different groups in the round.
+// ok is false if no such members exist in rounds which are not fixed.
+func (s *Schedule) randomSwap(rnd *rand.Rand) (r, a, b int, ok bool) {
+	mutable := len(s.rounds) - s.fixedRoundNum
+	if mutable <= 0 || len(s.members) < 2 {
+		return 0, 0, 0, false
+	}
+	// give up after a bounded number of attempts, e.g. when every round has a single group
+	for i := 0; i < 100; i++ {
+		r = s.fixedRoundNum + rnd.Intn(mutable)
+		a, b = rnd.Intn(len(s.members)), rnd.Intn(len(s.members))
+		rd := s.rounds[r]
+		if rd.groupIndex[a] != -1 && rd.groupIndex[b] != -1 && rd.groupIndex[a] != rd.groupIndex[b] && !rd.isPinned(a) && !rd.isPinned(b) {
+			return r, a, b, true
+		}
+	}
+	return 0, 0, 0, false
+}
+
+// shuffle assigns members of rounds which are not fixed to groups at random.
+// Sizes of groups are kept, and pinned members stay in their groups.
+func (s *Schedule) shuffle(rnd *rand.Rand) {
+	for _, rd := range s.rounds[s.fixedRoundNum:] {
+		var members []int
+		for _, group := range rd.groups {
+			s.addMeets(group, -1)
+			for _, m := range group {
+				if !rd.isPinned(m) {
+					members = append(members, m)
+				}
+			}
+		}
+		rnd.Shuffle(len(members), func(i, j int) {
+			members[i], members[j] = members[j], members[i]
+		})
+		for g, group := range rd.groups {
+			for i, m := range group {
+				if !rd.isPinned(m) {
+					group[i] = members[0]
+					members = members[1:]
+				}
+			}
+			for _, m := range group {
+				rd.groupIndex[m] = g
+			}
+			s.addMeets(group, 1)
+		}
+	}
+}
+
+func replace(group []int, old, new int) {
+	for i, m := range group {
+		if m == old {
+			group[i] = new
+			return
+		}
+	}
+}
+
+func (s *Schedule) clone() *Schedule {
+	c := newSchedule(s.members)
+	c.fixedRoundNum = s.fixedRoundNum
+	for i := range s.meets {
+		copy(c.meets[i], s.meets[i])
+	}
+	for _, rd := range s.rounds {
+		groups := make([][]int, len(rd.groups))
+		for g, group := range rd.groups {
+			groups[g] = append([]int{}, group...)
+		}
+		c.rounds = append(c.rounds, &round{
+			groupIndex: append([]int{}, rd.groupIndex...),
+			groups:     groups,
+			// pinned members never change, so pinned is shared
+			pinned: rd.pinned,
+		})
+	}
+	return c
+}
+
+// addGreedyRound appends a new round which attendees attend to the schedule.
+// Pinned members are assigned to their groups first and can not be moved by solvers.
+// Other attendees are assigned in random order to the group which has the fewest already met members.
+func (s *Schedule) addGreedyRound(capacities, attendees []int, pinned map[int]int, rnd *rand.Rand) error {
+	groups := make([][]int, len(capacities))
+	attends := map[int]bool{}
+	for _, m := range attendees {
+		attends[m] = true
+	}
+	var pinnedMembers []bool
+	for _, m := range sortedKeys(pinned) {
+		g := pinned[m]
+		switch {
+		case !attends[m]:
+			return fmt.Errorf("%s is pinned to group %d but absent", s.members[m].Name, g+1)
+		case g < 0 || g >= len(capacities):
+			return fmt.Errorf("%s is pinned to group %d but there are only %d groups", s.members[m].Name, g+1, len(capacities))
+		case len(groups[g]) >= capacities[g]:
+			return fmt.Errorf("too many members are pinned to group %d whose capacity is %d", g+1, capacities[g])
+		}
+		if pinnedMembers == nil {
+			pinnedMembers = make([]bool, len(s.members))
+		}
+		groups[g] = append(groups[g], m)
+		pinnedMembers[m] = true
+	}
+	for _, i := range rnd.Perm(len(attendees)) {
+		m := attendees[i]
+		if pinnedMembers != nil && pinnedMembers[m] {
+			continue
+		}
+		best, bestCost, ties := -1, 0, 0
+		for g, group := range groups {
+			if len(group) >= capacities[g] {
+				continue
+			}
+			cost := 0
+			for _, other := range group {
+				cost += s.meets[m][other]
+			}
+			switch {
+			case best == -1 || cost < bestCost:
+				best, bestCost, ties = g, cost, 1
+			case cost == bestCost:
+				// choose one of the tied groups uniformly at random
+				ties++
+				if rnd.Intn(ties) == 0 {
+					best = g
+				}
+			}
+		}
+		if best == -1 {
+			return fmt.Errorf("total group capacity(%d) is smaller than number of attendees(%d)", sum(capacities), len(attendees))
+		}
+		groups[best] = append(groups[best], m)
+	}
+	if err := s.addRound(groups); err != nil {
+		return err
+	}
+	s.rounds[len(s.rounds)-1].pinned = pinnedMembers
+	return nil
+}
+
+// sortedKeys returns keys of m in ascending order
+func sortedKeys(m map[int]int) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+// GroupCapacities returns capacities of groups which divide memberNum members into groups of groupSize as evenly as possible.
+// Leftover members make some groups one member smaller.
+// If that would make a group smaller than groupSize-1, leftover members are added to other groups instead.
+func GroupCapacities(memberNum, groupSize int) ([]int, error) {
+	if groupSize < 2 {
+		return nil, fmt.Errorf("group size must be greater than 1: %d", groupSize)
+	}
+	if memberNum < groupSize {
+		return nil, fmt.Errorf("number of members(%d) is smaller than group size(%d)", memberNum, groupSize)
+	}
+	groupNum := (memberNum + groupSize - 1) / groupSize
+	if minSize := memberNum / groupNum; minSize < groupSize-1 || minSize < 2 {
+		groupNum = memberNum / groupSize
+	}
+	capacities := make([]int, groupNum)
+	for i := range capacities {
+		capacities[i] = memberNum / groupNum
+		if i < memberNum%groupNum {
+			capacities[i]++
+		}
+	}
+	return capacities, nil
+}
+
+func sum(values []int) (total int) {
+	for _, v := range values {
+		total += v
+	}
+	return
+}
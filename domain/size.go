@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+)
+
+// GroupSizes decides sizes of groups in each round
+type GroupSizes struct {
+	// Size is preferred number of members in each group
+	Size int
+	// Min and Max bound number of members in each group if they are positive
+	Min, Max int
+	// Exact has sizes of groups of each round. The last sizes are used for the rest of rounds.
+	// Size, Min and Max are ignored if Exact is not empty.
+	Exact [][]int
+}
+
+// NewGroupSizes returns GroupSizes which divides members into groups of size as evenly as possible
+func NewGroupSizes(size int) *GroupSizes {
+	return &GroupSizes{Size: size}
+}
+
+// Capacities returns sizes of groups which memberNum members attend in round r (0-based)
+func (g *GroupSizes) Capacities(memberNum, r int) ([]int, error) {
+	if len(g.Exact) > 0 {
+		// the last sizes are used for the rest of rounds
+		i := r
+		if i >= len(g.Exact) {
+			i = len(g.Exact) - 1
+		}
+		capacities := append([]int{}, g.Exact[i]...)
+		if sum(capacities) != memberNum {
+			return nil, fmt.Errorf("sum of group sizes(%d) of round %d is not equal to number of members(%d)", sum(capacities), r+1, memberNum)
+		}
+		return capacities, nil
+	}
+	if g.Min <= 0 && g.Max <= 0 {
+		return GroupCapacities(memberNum, g.Size)
+	}
+	return boundedGroupCapacities(memberNum, g.Size, g.Min, g.Max)
+}
+
+// Design returns a known design which can be used for every round.
+// ok is false if groups of some rounds have different sizes.
+func (g *GroupSizes) Design(memberNum int) (design *Design, ok bool) {
+	if len(g.Exact) > 1 {
+		return nil, false
+	}
+	capacities, err := g.Capacities(memberNum, 0)
+	if err != nil {
+		return nil, false
+	}
+	for _, capacity := range capacities {
+		if capacity != capacities[0] {
+			return nil, false
+		}
+	}
+	return FindDesign(memberNum, capacities[0])
+}
+
+// boundedGroupCapacities returns capacities of groups whose sizes are between minSize and maxSize.
+// Number of groups is chosen so that average size is the closest to groupSize, and leftover members are spread over groups.
+func boundedGroupCapacities(memberNum, groupSize, minSize, maxSize int) ([]int, error) {
+	if minSize <= 0 {
+		minSize = 1
+	}
+	if maxSize <= 0 {
+		maxSize = memberNum
+	}
+	if minSize > maxSize {
+		return nil, fmt.Errorf("min group size(%d) is greater than max group size(%d)", minSize, maxSize)
+	}
+	groupNum := 0
+	bestDiff := math.Inf(1)
+	for n := (memberNum + maxSize - 1) / maxSize; n > 0 && n <= memberNum/minSize; n++ {
+		if diff := math.Abs(float64(memberNum)/float64(n) - float64(groupSize)); diff < bestDiff {
+			groupNum, bestDiff = n, diff
+		}
+	}
+	if groupNum == 0 {
+		return nil, fmt.Errorf("%d members can not be divided into groups of %d to %d members", memberNum, minSize, maxSize)
+	}
+	capacities := make([]int, groupNum)
+	for i := range capacities {
+		capacities[i] = memberNum / groupNum
+		if i < memberNum%groupNum {
+			capacities[i]++
+		}
+	}
+	return capacities, nil
+}
+
+// GroupSizeViolations returns groups which have fewer members than minSize or more members than maxSize.
+// Bounds which are not positive are ignored.
+func GroupSizeViolations(groupsList []Groups, minSize, maxSize int) (violations []*Violation) {
+	for r, groups := range groupsList {
+		for _, id := range groups.sortedIDList() {
+			size := len(groups[id].members)
+			var message string
+			switch {
+			case minSize > 0 && size < minSize:
+				message = fmt.Sprintf("size %d is smaller than min group size %d", size, minSize)
+			case maxSize > 0 && size > maxSize:
+				message = fmt.Sprintf("size %d is larger than max group size %d", size, maxSize)
+			default:
+				continue
+			}
+			violations = append(violations, &Violation{Round: r + 1, GroupID: id, Message: message})
+		}
+	}
+	return
+}
@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"context"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGroupSizes_Capacities(t *testing.T) {
+	tests := []struct {
+		name      string
+		sizes     *GroupSizes
+		memberNum int
+		round     int
+		want      []int
+		wantErr   bool
+	}{
+		{name: "preferred size", sizes: NewGroupSizes(4), memberNum: 10, want: []int{4, 3, 3}},
+		{name: "bounded by max", sizes: &GroupSizes{Size: 4, Max: 3}, memberNum: 10, want: []int{3, 3, 2, 2}},
+		{name: "bounded by min", sizes: &GroupSizes{Size: 4, Min: 4}, memberNum: 10, want: []int{5, 5}},
+		{name: "closest to preferred size", sizes: &GroupSizes{Size: 4, Min: 2, Max: 6}, memberNum: 13, want: []int{5, 4, 4}},
+		{name: "no way to divide", sizes: &GroupSizes{Size: 4, Min: 4, Max: 4}, memberNum: 10, wantErr: true},
+		{name: "exact sizes", sizes: &GroupSizes{Exact: [][]int{{5, 5}, {4, 3, 3}}}, memberNum: 10, want: []int{5, 5}},
+		{name: "exact sizes of the round", sizes: &GroupSizes{Exact: [][]int{{5, 5}, {4, 3, 3}}}, memberNum: 10, round: 1, want: []int{4, 3, 3}},
+		{name: "last exact sizes are repeated", sizes: &GroupSizes{Exact: [][]int{{5, 5}, {4, 3, 3}}}, memberNum: 10, round: 5, want: []int{4, 3, 3}},
+		{name: "exact sizes do not match", sizes: &GroupSizes{Exact: [][]int{{4, 4}}}, memberNum: 10, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.sizes.Capacities(tt.memberNum, tt.round)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Capacities() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Capacities() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// error names the requested round even if the last exact sizes are repeated for it
+	sizes := &GroupSizes{Exact: [][]int{{5, 5}, {4, 4}}}
+	if _, err := sizes.Capacities(10, 5); err == nil || !strings.Contains(err.Error(), "round 6") {
+		t.Errorf("Capacities() error = %v, want error of round 6", err)
+	}
+}
+
+func TestGroupSizes_Design(t *testing.T) {
+	if _, ok := NewGroupSizes(3).Design(9); !ok {
+		t.Errorf("Design() must be found for 9 members in groups of 3")
+	}
+	if _, ok := (&GroupSizes{Exact: [][]int{{3, 3, 3}, {4, 5}}}).Design(9); ok {
+		t.Errorf("Design() must not be found if group sizes differ between rounds")
+	}
+	if _, ok := (&GroupSizes{Size: 3, Max: 2}).Design(9); ok {
+		t.Errorf("Design() must not be found if groups have different sizes")
+	}
+}
+
+func TestGroupSizeViolations(t *testing.T) {
+	groupsList, err := parseGroupLines([][]string{
+		{"NAME", "1st", "2nd"},
+		{"alice", "1", "1"},
+		{"bob", "1", "2"},
+		{"carol", "1", "3"},
+		{"dave", "2", "1"},
+		{"ellen", "2", "2"},
+	})
+	if err != nil {
+		t.Fatalf("failed to parse group lines: %v", err)
+	}
+	var got []string
+	for _, v := range GroupSizeViolations(groupsList, 2, 2) {
+		got = append(got, v.String())
+	}
+	want := []string{
+		"round 1, group 1: size 3 is larger than max group size 2",
+		"round 2, group 3: size 1 is smaller than min group size 2",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GroupSizeViolations() = %q, want %q", got, want)
+	}
+	if got := GroupSizeViolations(groupsList, 0, 0); len(got) != 0 {
+		t.Errorf("GroupSizeViolations() without bounds = %v", got)
+	}
+}
+
+func TestGenerateGroups_GroupSizes(t *testing.T) {
+	members := newTestMembers("alice", "bob", "carol", "dave", "ellen", "frank", "grace", "heidi", "ivan", "judy")
+	sizes := &GroupSizes{Exact: [][]int{{5, 5}, {4, 3, 3}}}
+	got, err := GenerateGroups(context.Background(), members, nil, 3, sizes, 10, nil, NewAnnealingSolver(10000), rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatalf("GenerateGroups() error = %v", err)
+	}
+	s, err := NewScheduleFromGroupsList(members, got)
+	if err != nil {
+		t.Fatalf("failed to load groups: %v", err)
+	}
+	for r, capacities := range s.CapacitiesList() {
+		want, _ := sizes.Capacities(len(members), r)
+		if !reflect.DeepEqual(capacities, want) {
+			t.Errorf("GenerateGroups() sizes of round %d = %v, want %v", r+1, capacities, want)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package domain
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Solver improves rounds of a Schedule which are not fixed
+type Solver interface {
+	// Solve returns the best schedule found from s. s may be modified.
+	// Solve returns the best schedule found so far when ctx is done.
+	Solve(ctx context.Context, s *Schedule, cost Cost, rnd *rand.Rand) *Schedule
+}
+
+// ctxCheckInterval is number of iterations between checks of context cancellation
+const ctxCheckInterval = 1024
+
+// GreedySolver is a Solver which returns the initial schedule as it is
+type GreedySolver struct{}
+
+// Solve returns s
+func (g *GreedySolver) Solve(ctx context.Context, s *Schedule, cost Cost, rnd *rand.Rand) *Schedule {
+	return s
+}
+
+// AnnealingSolver is a Solver which uses simulated annealing.
+// Each iteration swaps two members of different groups in a random round,
+// and the temperature decreases geometrically from InitialTemperature to FinalTemperature.
+type AnnealingSolver struct {
+	// count is accessed atomically, so it is placed first to be 64-bit aligned
+	count              int64
+	Iterations         int
+	InitialTemperature float64
+	FinalTemperature   float64
+}
+
+// NewAnnealingSolver generates AnnealingSolver which has default temperatures
+func NewAnnealingSolver(iterations int) *AnnealingSolver {
+	return &AnnealingSolver{
+		Iterations:         iterations,
+		InitialTemperature: 2,
+		FinalTemperature:   0.01,
+	}
+}
+
+// Solve anneals s and returns the best schedule found
+func (a *AnnealingSolver) Solve(ctx context.Context, s *Schedule, cost Cost, rnd *rand.Rand) *Schedule {
+	current := cost.Cost(s)
+	best, bestCost := s.clone(), current
+	if a.Iterations <= 0 || a.InitialTemperature <= 0 || a.FinalTemperature <= 0 {
+		return best
+	}
+	cooling := math.Pow(a.FinalTemperature/a.InitialTemperature, 1/float64(a.Iterations))
+
+	temperature := a.InitialTemperature
+	i, counted := 0, 0
+	defer func() {
+		atomic.AddInt64(&a.count, int64(i-counted))
+	}()
+	for bound := lowerBound(cost); i < a.Iterations && bestCost > bound; i++ {
+		if i%ctxCheckInterval == 0 {
+			atomic.AddInt64(&a.count, int64(i-counted))
+			counted = i
+			if ctx.Err() != nil {
+				break
+			}
+		}
+		temperature *= cooling
+		r, m1, m2, ok := s.randomSwap(rnd)
+		if !ok {
+			break
+		}
+		delta := cost.SwapDelta(s, r, m1, m2)
+		if delta > 0 && rnd.Float64() >= math.Exp(-delta/temperature) {
+			continue
+		}
+		s.swap(r, m1, m2)
+		current += delta
+		if current < bestCost {
+			best, bestCost = s.clone(), current
+		}
+	}
+	return best
+}
+
+// IterationCount returns total number of iterations of all Solve calls
+func (a *AnnealingSolver) IterationCount() int64 {
+	return atomic.LoadInt64(&a.count)
+}
+
+// MultiStartSolver runs Solver from Starts starting points concurrently by Workers goroutines.
+// The first start uses the given schedule, and the others shuffle members in rounds which are not fixed.
+// If Starts is not positive, starts are repeated until ctx is done.
+// Cost must be safe for concurrent use.
+type MultiStartSolver struct {
+	Solver  Solver
+	Starts  int
+	Workers int
+	// OnProgress is called every ProgressInterval and when solving finishes if it is not nil
+	OnProgress       func(p *Progress)
+	ProgressInterval time.Duration
+	// OnStartDone is called when each start finishes if it is not nil
+	OnStartDone func(start int, cost float64)
+}
+
+// Progress is a snapshot of MultiStartSolver
+type Progress struct {
+	Elapsed  time.Duration
+	BestCost float64
+	// Starts is number of finished starts
+	Starts int
+	// Iterations is total number of iterations of all starts if Solver counts iterations
+	Iterations int64
+}
+
+// IterationsPerSecond returns average number of iterations per second
+func (p *Progress) IterationsPerSecond() float64 {
+	if p.Elapsed <= 0 {
+		return 0
+	}
+	return float64(p.Iterations) / p.Elapsed.Seconds()
+}
+
+// iterationCounter is implemented by solvers which count their iterations
+type iterationCounter interface {
+	IterationCount() int64
+}
+
+// NewMultiStartSolver generates MultiStartSolver.
+// Workers is GOMAXPROCS if workers is not positive, and Starts is Workers if starts is 0.
+func NewMultiStartSolver(solver Solver, starts, workers int) *MultiStartSolver {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if starts == 0 {
+		starts = workers
+	}
+	return &MultiStartSolver{Solver: solver, Starts: starts, Workers: workers, ProgressInterval: time.Second}
+}
+
+// Solve returns the best schedule of all starts.
+// Starts which have not begun when ctx is done are skipped.
+func (m *MultiStartSolver) Solve(ctx context.Context, s *Schedule, cost Cost, rnd *rand.Rand) *Schedule {
+	begin := time.Now()
+	// seeds are decided in advance so that the result does not depend on scheduling of goroutines
+	baseSeed := rnd.Int63()
+
+	var mu sync.Mutex
+	best := s.clone()
+	bestCost := cost.Cost(best)
+	bestStart, finished := -1, 0
+	update := func(start int, result *Schedule) {
+		c := cost.Cost(result)
+		mu.Lock()
+		defer mu.Unlock()
+		finished++
+		// ties are broken by start index to keep the result reproducible
+		if c < bestCost || (c == bestCost && (bestStart == -1 || start < bestStart)) {
+			best, bestCost, bestStart = result, c, start
+		}
+	}
+	progress := func() *Progress {
+		mu.Lock()
+		defer mu.Unlock()
+		p := &Progress{Elapsed: time.Since(begin), BestCost: bestCost, Starts: finished}
+		if counter, ok := m.Solver.(iterationCounter); ok {
+			p.Iterations = counter.IterationCount()
+		}
+		return p
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	if m.OnProgress != nil && m.ProgressInterval > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ticker := time.NewTicker(m.ProgressInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					m.OnProgress(progress())
+				case <-done:
+					return
+				}
+			}
+		}()
+	}
+
+	starts := make(chan int)
+	var workers sync.WaitGroup
+	for w := 0; w < m.Workers; w++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for start := range starts {
+				r := rand.New(rand.NewSource(baseSeed + int64(start)))
+				initial := s.clone()
+				if start > 0 {
+					initial.shuffle(r)
+				}
+				result := m.Solver.Solve(ctx, initial, cost, r)
+				update(start, result)
+				if m.OnStartDone != nil {
+					m.OnStartDone(start, cost.Cost(result))
+				}
+			}
+		}()
+	}
+	for start := 0; (m.Starts <= 0 || start < m.Starts) && ctx.Err() == nil; start++ {
+		select {
+		case starts <- start:
+		case <-ctx.Done():
+		}
+	}
+	close(starts)
+	workers.Wait()
+	close(done)
+	wg.Wait()
+
+	if m.OnProgress != nil {
+		m.OnProgress(progress())
+	}
+	return best
+}
@@ -0,0 +1,150 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func newTestSchedule(t *testing.T, memberNum, roundNum, groupSize int, rnd *rand.Rand) *Schedule {
+	t.Helper()
+	var names []string
+	for i := 0; i < memberNum; i++ {
+		names = append(names, fmt.Sprintf("member%d", i))
+	}
+	s := newSchedule(newTestMembers(names...))
+	capacities, err := GroupCapacities(memberNum, groupSize)
+	if err != nil {
+		t.Fatalf("failed to decide capacities: %v", err)
+	}
+	attendees := make([]int, memberNum)
+	for i := range attendees {
+		attendees[i] = i
+	}
+	for r := 0; r < roundNum; r++ {
+		if err := s.addGreedyRound(capacities, attendees, nil, rnd); err != nil {
+			t.Fatalf("failed to add round: %v", err)
+		}
+	}
+	return s
+}
+
+// assertSwapDelta applies n random swaps to s, and checks that SwapDelta of cost is equal to difference of Cost for each swap.
+// Cost must not be lower than lower bound of cost as well.
+func assertSwapDelta(t *testing.T, s *Schedule, cost Cost, rnd *rand.Rand, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		r, a, b, ok := s.randomSwap(rnd)
+		if !ok {
+			t.Fatalf("failed to find swap")
+		}
+		before := cost.Cost(s)
+		delta := cost.SwapDelta(s, r, a, b)
+		s.swap(r, a, b)
+		after := cost.Cost(s)
+		if got := after - before; math.Abs(got-delta) > 1e-9 {
+			t.Fatalf("SwapDelta() = %v, want %v", delta, got)
+		}
+		if bound := lowerBound(cost); after < bound-1e-9 {
+			t.Fatalf("Cost() = %v is lower than lower bound %v", after, bound)
+		}
+	}
+}
+
+func TestDupPairCost_SwapDelta(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	s := newTestSchedule(t, 12, 6, 3, rnd)
+	cost := DupPairCost{}
+	assertSwapDelta(t, s, cost, rnd, 100)
+	if dup, _ := CountDupMemberPairs(s.GroupsList()); float64(dup) != cost.Cost(s) {
+		t.Fatalf("Cost() = %v, want %v", cost.Cost(s), dup)
+	}
+}
+
+func TestAnnealingSolver_Solve(t *testing.T) {
+	tests := []struct {
+		name      string
+		memberNum int
+		roundNum  int
+		groupSize int
+		fixed     int
+		want      int
+	}{
+		{name: "affine plane of order 3", memberNum: 9, roundNum: 4, groupSize: 3, want: 0},
+		{name: "fixed rounds are kept", memberNum: 9, roundNum: 4, groupSize: 3, fixed: 2, want: 0},
+		{name: "duplication is unavoidable", memberNum: 4, roundNum: 4, groupSize: 2, want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rnd := rand.New(rand.NewSource(1))
+			s := newTestSchedule(t, tt.memberNum, tt.roundNum, tt.groupSize, rnd)
+			s.FixRounds(tt.fixed)
+			fixed := FormatGroupLines(s.members, s.GroupsList()[:tt.fixed])
+
+			got := NewAnnealingSolver(100000).Solve(context.Background(), s, DupPairCost{}, rnd)
+			if got.CountDup() != tt.want {
+				t.Errorf("Solve() dup = %v, want %v", got.CountDup(), tt.want)
+			}
+			if gotFixed := FormatGroupLines(got.members, got.GroupsList()[:tt.fixed]); fmt.Sprint(gotFixed) != fmt.Sprint(fixed) {
+				t.Errorf("Solve() changed fixed rounds: got = %v, want %v", gotFixed, fixed)
+			}
+		})
+	}
+}
+
+func TestMultiStartSolver_Solve(t *testing.T) {
+	solve := func(ctx context.Context, workers int) *Schedule {
+		rnd := rand.New(rand.NewSource(1))
+		s := newTestSchedule(t, 16, 6, 4, rnd)
+		return NewMultiStartSolver(NewAnnealingSolver(10000), 4, workers).Solve(ctx, s, DupPairCost{}, rnd)
+	}
+
+	got := solve(context.Background(), 4)
+	if dup, _ := CountDupMemberPairs(got.GroupsList()); dup != got.CountDup() {
+		t.Errorf("Solve() returns inconsistent schedule: dup = %v, want %v", got.CountDup(), dup)
+	}
+	if got.CountDup() != 24 {
+		t.Errorf("Solve() dup = %v, want %v", got.CountDup(), 24)
+	}
+	if other := solve(context.Background(), 1); fmt.Sprint(FormatGroupLines(other.members, other.GroupsList())) != fmt.Sprint(FormatGroupLines(got.members, got.GroupsList())) {
+		t.Errorf("Solve() depends on number of workers")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if canceled := solve(ctx, 4); canceled == nil || canceled.RoundNum() != 6 {
+		t.Errorf("Solve() must return initial schedule when context is canceled")
+	}
+}
+
+func TestMultiStartSolver_Solve_Unlimited(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	s := newTestSchedule(t, 32, 10, 4, rnd)
+	solver := NewMultiStartSolver(NewAnnealingSolver(1000), -1, 2)
+	var progresses []*Progress
+	solver.OnProgress = func(p *Progress) {
+		progresses = append(progresses, p)
+	}
+	solver.ProgressInterval = time.Hour
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	got := solver.Solve(ctx, s, DupPairCost{}, rnd)
+
+	if len(progresses) != 1 {
+		t.Fatalf("OnProgress must be called once when solving finishes: %d", len(progresses))
+	}
+	last := progresses[0]
+	if last.Starts <= 2 {
+		t.Errorf("starts must be repeated until deadline: %d", last.Starts)
+	}
+	if last.Iterations <= 2000 {
+		t.Errorf("iterations must be counted: %d", last.Iterations)
+	}
+	if last.BestCost != float64(got.CountDup()) {
+		t.Errorf("Progress.BestCost = %v, want %v", last.BestCost, got.CountDup())
+	}
+}
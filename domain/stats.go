@@ -0,0 +1,62 @@
+package domain
+
+// MemberStats is how many members a member has met in a schedule
+type MemberStats struct {
+	Name string
+	// Rounds is number of rounds which the member attends
+	Rounds int
+	// Met is number of distinct members whom the member has met
+	Met int
+	// Repeats is number of meetings with members whom the member has already met
+	Repeats int
+	// NeverMet are names of members whom the member has never met in order of members
+	NeverMet []string
+}
+
+// MemberStats returns stats of each member in order of members
+func (s *Schedule) MemberStats() []*MemberStats {
+	var stats []*MemberStats
+	for i, member := range s.members {
+		st := &MemberStats{Name: member.Name}
+		for _, rd := range s.rounds {
+			if rd.groupIndex[i] != -1 {
+				st.Rounds++
+			}
+		}
+		for j, other := range s.members {
+			if i == j {
+				continue
+			}
+			if n := s.meets[i][j]; n > 0 {
+				st.Met++
+				st.Repeats += n - 1
+			} else {
+				st.NeverMet = append(st.NeverMet, other.Name)
+			}
+		}
+		stats = append(stats, st)
+	}
+	return stats
+}
+
+// CumulativeMetPairs returns number of distinct member pairs who have met by the end of each round
+func (s *Schedule) CumulativeMetPairs() []int {
+	met := newSchedule(s.members).meets
+	cumulative := make([]int, len(s.rounds))
+	total := 0
+	for r, rd := range s.rounds {
+		for _, group := range rd.groups {
+			for i, a := range group {
+				for _, b := range group[i+1:] {
+					if met[a][b] == 0 {
+						total++
+					}
+					met[a][b]++
+					met[b][a]++
+				}
+			}
+		}
+		cumulative[r] = total
+	}
+	return cumulative
+}
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSchedule_MemberStats(t *testing.T) {
+	members, groupsList, err := parseGroupLinesWithMembers([][]string{
+		{"NAME", "1st", "2nd", "3rd"},
+		{"alice", "1", "1", "1"},
+		{"bob", "1", "2", "1"},
+		{"carol", "2", "1", ""},
+		{"dave", "2", "2", "2"},
+	})
+	if err != nil {
+		t.Fatalf("failed to parse groups: %v", err)
+	}
+	s, err := NewScheduleFromGroupsList(members, groupsList)
+	if err != nil {
+		t.Fatalf("failed to create schedule: %v", err)
+	}
+	want := []*MemberStats{
+		{Name: "alice", Rounds: 3, Met: 2, Repeats: 1, NeverMet: []string{"dave"}},
+		{Name: "bob", Rounds: 3, Met: 2, Repeats: 1, NeverMet: []string{"carol"}},
+		{Name: "carol", Rounds: 2, Met: 2, Repeats: 0, NeverMet: []string{"bob"}},
+		{Name: "dave", Rounds: 3, Met: 2, Repeats: 0, NeverMet: []string{"alice"}},
+	}
+	if got := s.MemberStats(); !reflect.DeepEqual(got, want) {
+		for i := range got {
+			t.Errorf("MemberStats()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestSchedule_CumulativeMetPairs(t *testing.T) {
+	members, groupsList, err := parseGroupLinesWithMembers([][]string{
+		{"NAME", "1st", "2nd", "3rd"},
+		{"alice", "1", "1", "1"},
+		{"bob", "1", "2", "1"},
+		{"carol", "2", "1", ""},
+		{"dave", "2", "2", "2"},
+	})
+	if err != nil {
+		t.Fatalf("failed to parse groups: %v", err)
+	}
+	s, err := NewScheduleFromGroupsList(members, groupsList)
+	if err != nil {
+		t.Fatalf("failed to create schedule: %v", err)
+	}
+	if got, want := s.CumulativeMetPairs(), []int{2, 4, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("CumulativeMetPairs() = %v, want %v", got, want)
+	}
+	if got := PairNum(len(members)); got != 6 {
+		t.Errorf("PairNum() = %v, want 6", got)
+	}
+}
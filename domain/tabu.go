@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"context"
+	"math/rand"
+	"sync/atomic"
+)
+
+// TabuSolver is a Solver which uses tabu search.
+// Each iteration evaluates every swap of two members of different groups in rounds which are not fixed,
+// and applies the best swap even if it makes the schedule worse.
+// Members which are swapped in a round can not be moved in the round again for Tenure iterations,
+// unless the swap finds a better schedule than the best one so far.
+type TabuSolver struct {
+	// count is accessed atomically, so it is placed first to be 64-bit aligned
+	count      int64
+	Iterations int
+	// Tenure is number of iterations in which swapped members are tabu. It is decided from number of members if not positive.
+	Tenure int
+}
+
+// NewTabuSolver generates TabuSolver which decides tenure from number of members
+func NewTabuSolver(iterations int) *TabuSolver {
+	return &TabuSolver{Iterations: iterations}
+}
+
+// Solve searches from s and returns the best schedule found
+func (t *TabuSolver) Solve(ctx context.Context, s *Schedule, cost Cost, rnd *rand.Rand) *Schedule {
+	current := cost.Cost(s)
+	best, bestCost := s.clone(), current
+	if len(s.rounds) <= s.fixedRoundNum {
+		return best
+	}
+	tenure := t.Tenure
+	if tenure <= 0 {
+		tenure = len(s.members)/4 + 1
+	}
+	// tabuUntil[r][m] is the iteration until which member m can not be moved in round r
+	tabuUntil := make([][]int, len(s.rounds))
+	for r := range tabuUntil {
+		tabuUntil[r] = make([]int, len(s.members))
+	}
+
+	i := 0
+	defer func() {
+		atomic.AddInt64(&t.count, int64(i))
+	}()
+	// an iteration evaluates all swaps, so context is checked every iteration
+	for bound := lowerBound(cost); i < t.Iterations && bestCost > bound && ctx.Err() == nil; i++ {
+		found, moveR, moveA, moveB, moveDelta, ties := false, 0, 0, 0, 0.0, 0
+		for r := s.fixedRoundNum; r < len(s.rounds); r++ {
+			rd := s.rounds[r]
+			for a := range s.members {
+				for b := a + 1; b < len(s.members); b++ {
+					ga, gb := rd.groupIndex[a], rd.groupIndex[b]
+					if ga == -1 || gb == -1 || ga == gb || rd.isPinned(a) || rd.isPinned(b) {
+						continue
+					}
+					delta := cost.SwapDelta(s, r, a, b)
+					tabu := tabuUntil[r][a] > i || tabuUntil[r][b] > i
+					// aspiration: a tabu swap is allowed if it finds a new best schedule
+					if tabu && current+delta >= bestCost {
+						continue
+					}
+					switch {
+					case !found || delta < moveDelta:
+						found, moveR, moveA, moveB, moveDelta, ties = true, r, a, b, delta, 1
+					case delta == moveDelta:
+						// choose one of the tied swaps uniformly at random
+						ties++
+						if rnd.Intn(ties) == 0 {
+							moveR, moveA, moveB = r, a, b
+						}
+					}
+				}
+			}
+		}
+		if !found {
+			continue
+		}
+		s.swap(moveR, moveA, moveB)
+		current += moveDelta
+		tabuUntil[moveR][moveA], tabuUntil[moveR][moveB] = i+tenure, i+tenure
+		if current < bestCost {
+			best, bestCost = s.clone(), current
+		}
+	}
+	return best
+}
+
+// IterationCount returns total number of iterations of all Solve calls
+func (t *TabuSolver) IterationCount() int64 {
+	return atomic.LoadInt64(&t.count)
+}
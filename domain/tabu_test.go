@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func TestTabuSolver_Solve(t *testing.T) {
+	tests := []struct {
+		name      string
+		memberNum int
+		roundNum  int
+		groupSize int
+		fixed     int
+		want      int
+	}{
+		{name: "affine plane of order 3", memberNum: 9, roundNum: 4, groupSize: 3, want: 0},
+		{name: "fixed rounds are kept", memberNum: 9, roundNum: 4, groupSize: 3, fixed: 2, want: 0},
+		{name: "duplication is unavoidable", memberNum: 4, roundNum: 4, groupSize: 2, want: 2},
+		{name: "kirkman schoolgirl problem", memberNum: 15, roundNum: 5, groupSize: 3, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rnd := rand.New(rand.NewSource(1))
+			s := newTestSchedule(t, tt.memberNum, tt.roundNum, tt.groupSize, rnd)
+			s.FixRounds(tt.fixed)
+			fixed := FormatGroupLines(s.members, s.GroupsList()[:tt.fixed])
+
+			solver := NewTabuSolver(5000)
+			got := solver.Solve(context.Background(), s, DupPairCost{}, rnd)
+			if got.CountDup() != tt.want {
+				t.Errorf("Solve() dup = %v, want %v", got.CountDup(), tt.want)
+			}
+			if gotFixed := FormatGroupLines(got.members, got.GroupsList()[:tt.fixed]); fmt.Sprint(gotFixed) != fmt.Sprint(fixed) {
+				t.Errorf("Solve() changed fixed rounds: got = %v, want %v", gotFixed, fixed)
+			}
+			if solver.IterationCount() == 0 && tt.want > 0 {
+				t.Errorf("IterationCount() must be counted")
+			}
+		})
+	}
+}
+
+func TestTabuSolver_Solve_Cancel(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	s := newTestSchedule(t, 32, 10, 4, rnd)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	solver := NewTabuSolver(1000000)
+	got := solver.Solve(ctx, s.clone(), DupPairCost{}, rnd)
+	if got.CountDup() != s.CountDup() {
+		t.Errorf("Solve() must return initial schedule when ctx is done: got = %v, want %v", got.CountDup(), s.CountDup())
+	}
+	if solver.IterationCount() != 0 {
+		t.Errorf("IterationCount() = %v, want 0", solver.IterationCount())
+	}
+}
@@ -0,0 +1,169 @@
+package domain
+
+import (
+	"encoding/csv"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+)
+
+// PairWeight is cost of each meeting of two members, which overrides penalty of duplicated meetings of the pair.
+// Positive Weight keeps the members apart, and negative Weight means that the members are wanted to meet.
+type PairWeight struct {
+	Names  [2]string
+	Weight float64
+}
+
+func (p *PairWeight) String() string {
+	return fmt.Sprintf("%s and %s: %g", p.Names[0], p.Names[1], p.Weight)
+}
+
+// ParsePairWeightFile parses pair weight csv file. Each line has two names and a weight such as "alice,bob,3".
+func ParsePairWeightFile(filePath string) ([]*PairWeight, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file from %s", filePath)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.Comment = commentPrefix
+	reader.TrimLeadingSpace = true
+	lines, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse csv from %s: %w", filePath, err)
+	}
+	return parsePairWeightLines(lines)
+}
+
+func parsePairWeightLines(lines [][]string) ([]*PairWeight, error) {
+	var weights []*PairWeight
+	for i, line := range lines {
+		if len(line) != 3 {
+			return nil, fmt.Errorf("pair weight must have two names and a weight at line %d: %s", i+1, line)
+		}
+		if line[0] == line[1] {
+			return nil, fmt.Errorf("pair weight must have different names at line %d: %s", i+1, line)
+		}
+		weight, err := strconv.ParseFloat(line[2], 64)
+		if err != nil || math.IsNaN(weight) || math.IsInf(weight, 0) {
+			return nil, fmt.Errorf("invalid weight at line %d: %s", i+1, line[2])
+		}
+		weights = append(weights, &PairWeight{Names: [2]string{line[0], line[1]}, Weight: weight})
+	}
+	return weights, nil
+}
+
+// pairWeightCost is a Cost which replaces cost of weighted pairs in base with their weights multiplied by number of meetings
+type pairWeightCost struct {
+	base pairTerm
+	// partners has weighted pairs of each member
+	partners [][]weightedPartner
+	negative bool
+}
+
+type weightedPartner struct {
+	other  int
+	weight float64
+}
+
+func newPairWeightCost(members []*Member, base pairTerm, weights []*PairWeight) (*pairWeightCost, error) {
+	indexes := map[string]int{}
+	for i, member := range members {
+		indexes[member.Name] = i
+	}
+	c := &pairWeightCost{base: base, partners: make([][]weightedPartner, len(members))}
+	listed := map[[2]int]bool{}
+	for _, w := range weights {
+		a, ok := indexes[w.Names[0]]
+		if !ok {
+			return nil, fmt.Errorf("unknown member in pair weight(%s): %s", w, w.Names[0])
+		}
+		b, ok := indexes[w.Names[1]]
+		if !ok {
+			return nil, fmt.Errorf("unknown member in pair weight(%s): %s", w, w.Names[1])
+		}
+		if a > b {
+			a, b = b, a
+		}
+		if listed[[2]int{a, b}] {
+			return nil, fmt.Errorf("pair weight is specified more than once: %s and %s", w.Names[0], w.Names[1])
+		}
+		listed[[2]int{a, b}] = true
+		c.partners[a] = append(c.partners[a], weightedPartner{other: b, weight: w.Weight})
+		c.partners[b] = append(c.partners[b], weightedPartner{other: a, weight: w.Weight})
+		c.negative = c.negative || w.Weight < 0
+	}
+	return c, nil
+}
+
+// Cost returns cost of base in which weighted pairs cost their weights for each meeting
+func (c *pairWeightCost) Cost(s *Schedule) float64 {
+	total := c.base.Cost(s)
+	for a, partners := range c.partners {
+		for _, p := range partners {
+			if a < p.other {
+				total += p.weight*float64(s.meets[a][p.other]) - c.base.pairCost(s, a, p.other)
+			}
+		}
+	}
+	return total
+}
+
+// SwapDelta returns difference of cost caused by swap
+func (c *pairWeightCost) SwapDelta(s *Schedule, r, a, b int) float64 {
+	rd := s.rounds[r]
+	ga, gb := rd.groupIndex[a], rd.groupIndex[b]
+	if ga == gb {
+		return 0
+	}
+	delta := c.base.SwapDelta(s, r, a, b)
+	// member a leaves group ga and joins group gb, and vice versa
+	for _, move := range [][3]int{{a, ga, gb}, {b, gb, ga}} {
+		m, from, to := move[0], move[1], move[2]
+		for _, p := range c.partners[m] {
+			if p.other == a || p.other == b {
+				continue
+			}
+			switch rd.groupIndex[p.other] {
+			case from:
+				delta += -p.weight - c.base.toggleDelta(s, r, m, p.other, false)
+			case to:
+				delta += p.weight - c.base.toggleDelta(s, r, m, p.other, true)
+			}
+		}
+	}
+	return delta
+}
+
+// roundRange returns range of base and absolute weights of pairs which meet at most once in a round
+func (c *pairWeightCost) roundRange(memberNum int) float64 {
+	total := roundRange(c.base, memberNum)
+	for a, partners := range c.partners {
+		for _, p := range partners {
+			if a < p.other {
+				total += math.Abs(p.weight)
+			}
+		}
+	}
+	return total
+}
+
+// lowerBound returns 0 if no weight is negative, and otherwise cost is not bounded below for solvers
+func (c *pairWeightCost) lowerBound() float64 {
+	if c.negative {
+		return math.Inf(-1)
+	}
+	return 0
+}
+
+// WeightedScore returns cost of s in which duplicated member pairs cost 1, or penalty decided by decay if it is not nil,
+// and pairs in weights cost their weights for each meeting
+func WeightedScore(s *Schedule, decay Decay, weights []*PairWeight) (float64, error) {
+	cost, err := (&Objective{Decay: decay, PairWeights: weights}).newCost(s.members)
+	if err != nil {
+		return 0, err
+	}
+	return cost.Cost(s), nil
+}
@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"context"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func Test_parsePairWeightLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		lines   [][]string
+		want    []*PairWeight
+		wantErr bool
+	}{
+		{
+			name:  "positive and negative weights",
+			lines: [][]string{{"alice", "bob", "3"}, {"carol", "dave", "-0.5"}},
+			want: []*PairWeight{
+				{Names: [2]string{"alice", "bob"}, Weight: 3},
+				{Names: [2]string{"carol", "dave"}, Weight: -0.5},
+			},
+		},
+		{name: "missing weight", lines: [][]string{{"alice", "bob"}}, wantErr: true},
+		{name: "same name", lines: [][]string{{"alice", "alice", "1"}}, wantErr: true},
+		{name: "invalid weight", lines: [][]string{{"alice", "bob", "heavy"}}, wantErr: true},
+		{name: "infinite weight", lines: [][]string{{"alice", "bob", "Inf"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePairWeightLines(tt.lines)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePairWeightLines() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parsePairWeightLines() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWeightedScore(t *testing.T) {
+	members, groupsList, err := parseGroupLinesWithMembers([][]string{
+		{"NAME", "1st", "2nd", "3rd"},
+		{"alice", "1", "1", "1"},
+		{"bob", "1", "1", "2"},
+		{"carol", "2", "2", "1"},
+		{"dave", "2", "2", "2"},
+	})
+	if err != nil {
+		t.Fatalf("failed to parse group lines: %v", err)
+	}
+	s, err := NewScheduleFromGroupsList(members, groupsList)
+	if err != nil {
+		t.Fatalf("failed to create schedule: %v", err)
+	}
+	weights := []*PairWeight{
+		{Names: [2]string{"alice", "bob"}, Weight: 3},
+		{Names: [2]string{"dave", "carol"}, Weight: -1},
+	}
+	// alice and bob meet twice, carol and dave meet twice, and alice and carol meet once
+	got, err := WeightedScore(s, nil, weights)
+	if err != nil {
+		t.Fatalf("WeightedScore() error = %v", err)
+	}
+	if want := 3.0*2 - 1*2; got != want {
+		t.Errorf("WeightedScore() = %v, want %v", got, want)
+	}
+	if _, err := WeightedScore(s, nil, []*PairWeight{{Names: [2]string{"alice", "zoe"}, Weight: 1}}); err == nil {
+		t.Errorf("WeightedScore() must fail if weights have unknown member")
+	}
+}
+
+func Test_pairWeightCost_SwapDelta(t *testing.T) {
+	for _, base := range []pairTerm{DupPairCost{}, &decayedDupCost{decay: &ExponentialDecay{HalfLife: 2}}} {
+		rnd := rand.New(rand.NewSource(1))
+		s := newTestSchedule(t, 10, 5, 3, rnd)
+		var weights []*PairWeight
+		for i := 0; i < 6; i++ {
+			weights = append(weights, &PairWeight{Names: [2]string{s.members[i].Name, s.members[(i*3+1)%10].Name}, Weight: float64(i) - 2.5})
+		}
+		cost, err := newPairWeightCost(s.members, base, weights)
+		if err != nil {
+			t.Fatalf("failed to create cost: %v", err)
+		}
+		assertSwapDelta(t, s, cost, rnd, 200)
+	}
+}
+
+func TestGenerateGroups_PairWeights(t *testing.T) {
+	members := newTestMembers("alice", "bob", "carol", "dave", "ellen", "frank", "grace", "heidi", "ivan")
+	weights := []*PairWeight{
+		{Names: [2]string{"alice", "bob"}, Weight: 5},
+		{Names: [2]string{"carol", "dave"}, Weight: -5},
+	}
+	rnd := rand.New(rand.NewSource(1))
+	got, err := GenerateGroups(context.Background(), members, nil, 4, NewGroupSizes(3), 10, &Objective{PairWeights: weights}, NewAnnealingSolver(100000), rnd)
+	if err != nil {
+		t.Fatalf("GenerateGroups() error = %v", err)
+	}
+	for r, groups := range got {
+		ids := groups.groupIDMap()
+		if ids["alice"] == ids["bob"] {
+			t.Errorf("alice and bob must be apart in round %d", r+1)
+		}
+		if ids["carol"] != ids["dave"] {
+			t.Errorf("carol and dave must meet in round %d", r+1)
+		}
+	}
+}